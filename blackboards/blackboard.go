@@ -0,0 +1,201 @@
+// Copyright 2026 Jeffrey Bester <jbester@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and
+// to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of
+// the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package blackboards provides the Blackboard, modelled on the ARINC 653 blackboard service.  A
+// blackboard holds at most one message.  Displaying a message replaces any message already
+// displayed and releases all routines waiting to read.  Reading does not remove the message; every
+// reader gets the latest displayed message until the blackboard is cleared.  A TypedBlackboard
+// holds messages of a single type.
+//
+// Unlike pairing an events.Event with a separately locked message, displaying a new message never
+// passes through an unset state, so readers cannot miss an update.
+package blackboards
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"bitbucket.org/jbester/sync/clock"
+	"bitbucket.org/jbester/sync/watchdog"
+)
+
+// A Blackboard holds the latest displayed message for any number of readers.
+type Blackboard interface {
+	//  Display a message, replacing any message already displayed.  All routines waiting
+	//  to read are released with the message.
+	Display(message interface{})
+
+	//  Clear the displayed message.  Subsequent reads block until a message is displayed.
+	Clear()
+
+	//  Read the displayed message, waiting until one is displayed.
+	Read() interface{}
+
+	//  Read the displayed message, waiting up to the timeout for one to be displayed.  Returns
+	//  false if no message was displayed before the timeout.
+	TimedRead(timeout time.Duration) (interface{}, bool)
+
+	//  Read the displayed message, waiting until one is displayed or the context is done.
+	//  Returns the context's error if the context finished first.
+	ReadContext(ctx context.Context) (interface{}, error)
+
+	//  IsEmpty returns true if no message is displayed.
+	IsEmpty() bool
+
+	//  Waiting returns the number of routines waiting to read.
+	Waiting() int32
+}
+
+// A TypedBlackboard is a Blackboard holding messages of type T.
+type TypedBlackboard[T any] interface {
+	//  Display a message, replacing any message already displayed.  All routines waiting
+	//  to read are released with the message.
+	Display(message T)
+
+	//  Clear the displayed message.  Subsequent reads block until a message is displayed.
+	Clear()
+
+	//  Read the displayed message, waiting until one is displayed.
+	Read() T
+
+	//  Read the displayed message, waiting up to the timeout for one to be displayed.  Returns
+	//  false if no message was displayed before the timeout.
+	TimedRead(timeout time.Duration) (T, bool)
+
+	//  Read the displayed message, waiting until one is displayed or the context is done.
+	//  Returns the context's error if the context finished first.
+	ReadContext(ctx context.Context) (T, error)
+
+	//  IsEmpty returns true if no message is displayed.
+	IsEmpty() bool
+
+	//  Waiting returns the number of routines waiting to read.
+	Waiting() int32
+}
+
+// A display event and the message it carries.  The message is written before the channel is
+// closed, so released readers get the message displayed even if it is cleared again at once.
+type display[T any] struct {
+	done    chan struct{}
+	message T
+}
+
+type blackboard[T any] struct {
+	lock      *sync.RWMutex
+	message   T
+	displayed bool
+	waiting   int32
+	pending   *display[T]
+	clock     clock.Clock
+	watchdog  watchdog.Watchdog
+}
+
+// Creates an empty blackboard for use by any routine.
+func MakeBlackboard(opts ...Option) Blackboard {
+	return MakeTypedBlackboard[interface{}](opts...)
+}
+
+// Creates an empty blackboard of messages of type T for use by any routine.
+func MakeTypedBlackboard[T any](opts ...Option) TypedBlackboard[T] {
+	var options = makeOptions(opts)
+	return &blackboard[T]{
+		lock:     &sync.RWMutex{},
+		pending:  &display[T]{done: make(chan struct{})},
+		clock:    options.clock,
+		watchdog: options.watchdog,
+	}
+}
+
+// Returns the displayed message and true if there is one; otherwise the next display event.
+func (board *blackboard[T]) current() (T, bool, *display[T]) {
+	board.lock.RLock()
+	defer board.lock.RUnlock()
+	if board.displayed {
+		return board.message, true, nil
+	}
+	var zero T
+	return zero, false, board.pending
+}
+
+func (board *blackboard[T]) read(expired <-chan time.Time, done <-chan struct{}) (T, bool) {
+	var message, ok, next = board.current()
+	if ok {
+		return message, true
+	}
+
+	atomic.AddInt32(&board.waiting, 1)
+	defer atomic.AddInt32(&board.waiting, -1)
+	defer board.watchdog.Begin("blackboard")()
+	select {
+	case <-next.done:
+		// the message is delivered by the display itself; it may already have been cleared
+		return next.message, true
+	case <-expired:
+		return message, false
+	case <-done:
+		return message, false
+	}
+}
+
+func (board *blackboard[T]) Display(message T) {
+	var next = &display[T]{done: make(chan struct{})}
+	board.lock.Lock()
+	defer board.lock.Unlock()
+	board.message = message
+	board.displayed = true
+	var old = board.pending
+	board.pending = next
+	old.message = message
+	close(old.done)
+}
+
+func (board *blackboard[T]) Clear() {
+	var zero T
+	board.lock.Lock()
+	defer board.lock.Unlock()
+	board.message = zero
+	board.displayed = false
+}
+
+func (board *blackboard[T]) Read() T {
+	var message, _ = board.read(nil, nil)
+	return message
+}
+
+func (board *blackboard[T]) TimedRead(timeout time.Duration) (T, bool) {
+	var timer = board.clock.NewTimer(timeout)
+	defer timer.Stop()
+	return board.read(timer.C(), nil)
+}
+
+func (board *blackboard[T]) ReadContext(ctx context.Context) (T, error) {
+	var message, ok = board.read(nil, ctx.Done())
+	if !ok {
+		return message, ctx.Err()
+	}
+	return message, nil
+}
+
+func (board *blackboard[T]) IsEmpty() bool {
+	board.lock.RLock()
+	defer board.lock.RUnlock()
+	return !board.displayed
+}
+
+func (board *blackboard[T]) Waiting() int32 {
+	return atomic.LoadInt32(&board.waiting)
+}
@@ -0,0 +1,166 @@
+// Copyright 2026 Jeffrey Bester <jbester@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and
+// to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of
+// the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package blackboards
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"bitbucket.org/jbester/sync/clock"
+	"bitbucket.org/jbester/sync/watchdog"
+	"github.com/stretchr/testify/assert"
+)
+
+// wait until the given number of routines are waiting to read
+func waitForReaders(board Blackboard, count int32) {
+	for board.Waiting() != count {
+		<-time.After(time.Millisecond)
+	}
+}
+
+func Test_DisplayRead(t *testing.T) {
+	var board = MakeBlackboard()
+	assert.True(t, board.IsEmpty())
+	board.Display("first")
+	assert.False(t, board.IsEmpty())
+	assert.Equal(t, "first", board.Read())
+	// reading does not remove the message
+	assert.Equal(t, "first", board.Read())
+	board.Display("second")
+	assert.Equal(t, "second", board.Read())
+}
+
+func Test_Clear(t *testing.T) {
+	var board = MakeBlackboard()
+	board.Display(1)
+	board.Clear()
+	assert.True(t, board.IsEmpty())
+	var message, ok = board.TimedRead(time.Millisecond)
+	assert.False(t, ok)
+	assert.Nil(t, message)
+}
+
+func Test_ReadersReleased(t *testing.T) {
+	var board = MakeBlackboard()
+	var done = &sync.WaitGroup{}
+	for i := 0; i < 5; i++ {
+		done.Add(1)
+		go func() {
+			defer done.Done()
+			assert.Equal(t, 42, board.Read())
+		}()
+	}
+	waitForReaders(board, 5)
+	board.Display(42)
+	done.Wait()
+	assert.Equal(t, int32(0), board.Waiting())
+}
+
+func Test_ReadContext(t *testing.T) {
+	var board = MakeBlackboard()
+	var ctx, cancel = context.WithCancel(context.Background())
+	var result = make(chan error)
+	go func() {
+		var _, err = board.ReadContext(ctx)
+		result <- err
+	}()
+	waitForReaders(board, 1)
+	cancel()
+	assert.Equal(t, context.Canceled, <-result)
+	assert.Equal(t, int32(0), board.Waiting())
+
+	board.Display("message")
+	var message, err = board.ReadContext(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "message", message)
+}
+
+func Test_ReaderSeesLatestAfterClear(t *testing.T) {
+	var board = MakeBlackboard()
+	board.Display("old")
+	board.Clear()
+	var result = make(chan interface{})
+	go func() {
+		result <- board.Read()
+	}()
+	waitForReaders(board, 1)
+	board.Display("new")
+	assert.Equal(t, "new", <-result)
+}
+
+// Verify a waiting reader gets a message displayed and cleared again before it runs
+func Test_ReaderReleasedWithClearedMessage(t *testing.T) {
+	var board = MakeBlackboard()
+	var result = make(chan interface{})
+	go func() {
+		var message, ok = board.TimedRead(time.Minute)
+		assert.True(t, ok)
+		result <- message
+	}()
+	waitForReaders(board, 1)
+	board.Display("hello")
+	board.Clear()
+	assert.Equal(t, "hello", <-result)
+	assert.True(t, board.IsEmpty())
+}
+
+func Test_TypedBlackboard(t *testing.T) {
+	var board = MakeTypedBlackboard[int]()
+	var value, ok = board.TimedRead(time.Millisecond)
+	assert.False(t, ok)
+	assert.Equal(t, 0, value)
+	var result = make(chan int)
+	go func() {
+		result <- board.Read()
+	}()
+	board.Display(11)
+	assert.Equal(t, 11, <-result)
+	board.Clear()
+	assert.True(t, board.IsEmpty())
+}
+
+func Test_TimedReadFakeClock(t *testing.T) {
+	var fake = clock.MakeFakeClock(time.Now())
+	var board = MakeBlackboard(WithClock(fake))
+	var result = make(chan bool)
+	go func() {
+		var _, ok = board.TimedRead(time.Hour)
+		result <- ok
+	}()
+	fake.WaitForTimers(1)
+	fake.Advance(time.Hour)
+	assert.False(t, <-result)
+}
+
+// Verify a blocked wait is recorded by the watchdog
+func Test_BlackboardWatchdog(t *testing.T) {
+	var dog = watchdog.MakeWatchdog(time.Hour)
+	defer dog.Stop()
+	var board = MakeBlackboard(WithWatchdog(dog))
+	var done = make(chan struct{})
+	go func() {
+		board.Read()
+		close(done)
+	}()
+	for len(dog.Waits()) == 0 {
+		<-time.After(time.Millisecond)
+	}
+	assert.Equal(t, "blackboard", dog.Waits()[0].Kind)
+	board.Display(1)
+	<-done
+	assert.Empty(t, dog.Waits())
+}
@@ -0,0 +1,51 @@
+// Copyright 2026 Jeffrey Bester <jbester@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and
+// to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of
+// the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package blackboards
+
+import (
+	"bitbucket.org/jbester/sync/clock"
+	"bitbucket.org/jbester/sync/watchdog"
+)
+
+// Option configures optional behaviour of a blackboard when it is created.
+type Option func(*options)
+
+type options struct {
+	clock    clock.Clock
+	watchdog watchdog.Watchdog
+}
+
+// WithClock makes a blackboard use the clock for timed operations in place of the system clock.
+func WithClock(c clock.Clock) Option {
+	return func(opts *options) {
+		opts.clock = c
+	}
+}
+
+// WithWatchdog makes a blackboard record its blocked waits with the watchdog.
+func WithWatchdog(w watchdog.Watchdog) Option {
+	return func(opts *options) {
+		opts.watchdog = w
+	}
+}
+
+func makeOptions(opts []Option) options {
+	var result = options{clock: clock.Real(), watchdog: watchdog.Disabled()}
+	for _, opt := range opts {
+		opt(&result)
+	}
+	return result
+}
@@ -0,0 +1,313 @@
+// Copyright 2026 Jeffrey Bester <jbester@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and
+// to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of
+// the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package buffers provides the Buffer, a bounded message queue modelled on the ARINC 653 buffer
+// service.  A buffer holds up to a fixed number of messages.  Senders block while the buffer is
+// full and receivers block while it is empty.  Blocked routines are served in arrival order or
+// highest priority first depending on the queuing discipline chosen when the buffer is created.
+//
+// Slots and messages are accounted for with priority counting semaphores from the semaphores
+// package.
+package buffers
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"bitbucket.org/jbester/sync/semaphores"
+	"bitbucket.org/jbester/sync/tasks"
+	"bitbucket.org/jbester/sync/watchdog"
+)
+
+// QueuingDiscipline selects the order in which blocked senders and receivers are served.
+type QueuingDiscipline int
+
+const (
+	// Serve blocked routines in arrival order.  Priorities given to a buffer are ignored.
+	Fifo QueuingDiscipline = iota
+
+	// Serve blocked routines highest priority first; equal priorities in arrival order.
+	Priority
+)
+
+// A Buffer is a bounded queue of messages of type T.  Methods that do not take a priority wait at
+// semaphores.DefaultPriority.
+type Buffer[T any] interface {
+	//  Send a message, waiting while the buffer is full.
+	Send(message T)
+
+	//  Send a message, waiting up to the timeout while the buffer is full.  Returns false if
+	//  the message was not sent.
+	TimedSend(message T, timeout time.Duration) bool
+
+	//  Send a message, waiting while the buffer is full until the context is done.  Returns
+	//  the context's error if the message was not sent.
+	SendContext(ctx context.Context, message T) error
+
+	//  Send a message, waiting at the given priority while the buffer is full.
+	SendPriority(priority int, message T)
+
+	//  Send a message, waiting at the given priority up to the timeout while the buffer is
+	//  full.  Returns false if the message was not sent.
+	TimedSendPriority(priority int, message T, timeout time.Duration) bool
+
+	//  Send a message, waiting at the task's priority while the buffer is full.  The wait
+	//  follows changes to the task's priority.
+	SendTask(task tasks.Task, message T)
+
+	//  Receive the oldest message, waiting while the buffer is empty.
+	Receive() T
+
+	//  Receive the oldest message, waiting up to the timeout while the buffer is empty.
+	//  Returns false if no message was received.
+	TimedReceive(timeout time.Duration) (T, bool)
+
+	//  Receive the oldest message, waiting while the buffer is empty until the context is done.
+	//  Returns the context's error if no message was received.
+	ReceiveContext(ctx context.Context) (T, error)
+
+	//  Receive the oldest message, waiting at the given priority while the buffer is empty.
+	ReceivePriority(priority int) T
+
+	//  Receive the oldest message, waiting at the given priority up to the timeout while the
+	//  buffer is empty.  Returns false if no message was received.
+	TimedReceivePriority(priority int, timeout time.Duration) (T, bool)
+
+	//  Receive the oldest message, waiting at the task's priority while the buffer is empty.
+	//  The wait follows changes to the task's priority.
+	ReceiveTask(task tasks.Task) T
+
+	//  Pending returns the number of messages in the buffer.
+	Pending() int32
+
+	//  Capacity returns the maximum number of messages the buffer holds.
+	Capacity() int32
+
+	//  WaitingSenders returns the number of routines blocked sending.
+	WaitingSenders() int32
+
+	//  WaitingReceivers returns the number of routines blocked receiving.
+	WaitingReceivers() int32
+
+	//  Discipline returns the queuing discipline of the buffer.
+	Discipline() QueuingDiscipline
+}
+
+type buffer[T any] struct {
+	lock             *sync.Mutex
+	messages         []T
+	head             int32
+	pending          int32
+	slots            semaphores.PrioritySemaphore
+	available        semaphores.PrioritySemaphore
+	waitingSenders   int32
+	waitingReceivers int32
+	discipline       QueuingDiscipline
+	watchdog         watchdog.Watchdog
+}
+
+// Blocks on a semaphore at a priority until a unit is taken.  Returns an error if the unit was
+// not taken.
+type blocker func(semaphore semaphores.PrioritySemaphore, priority int) error
+
+// Returned internally when a timed operation expires.
+var errTimeout = errors.New("buffer operation timed out")
+
+// Block until the timeout expires.
+func untilTimeout(timeout time.Duration) blocker {
+	return func(semaphore semaphores.PrioritySemaphore, priority int) error {
+		if !semaphore.TryTakePriority(priority, timeout) {
+			return errTimeout
+		}
+		return nil
+	}
+}
+
+// Block until the context is done.
+func untilDone(ctx context.Context) blocker {
+	return func(semaphore semaphores.PrioritySemaphore, priority int) error {
+		return semaphore.TakePriorityContext(ctx, priority)
+	}
+}
+
+// Block at the task's priority until the unit is taken.
+func untilTaken(task tasks.Task) blocker {
+	return func(semaphore semaphores.PrioritySemaphore, priority int) error {
+		semaphore.TakeTask(task)
+		return nil
+	}
+}
+
+// Create a buffer holding up to capacity messages whose blocked routines are served according to
+// the queuing discipline.
+func MakeBuffer[T any](capacity int32, discipline QueuingDiscipline, opts ...Option) Buffer[T] {
+	if capacity < 1 {
+		panic("buffer created with capacity less than one")
+	}
+	var options = makeOptions(opts)
+	var withClock = semaphores.WithClock(options.clock)
+	return &buffer[T]{
+		lock:       &sync.Mutex{},
+		messages:   make([]T, capacity),
+		slots:      semaphores.MakePriorityCountingSemaphore(capacity, capacity, withClock),
+		available:  semaphores.MakePriorityCountingSemaphore(0, capacity, withClock),
+		discipline: discipline,
+		watchdog:   options.watchdog,
+	}
+}
+
+// Returns the priority to wait at under the buffer's queuing discipline.
+func (buf *buffer[T]) priority(priority int) int {
+	if buf.discipline == Fifo {
+		return semaphores.DefaultPriority
+	}
+	return priority
+}
+
+// Take a unit of the semaphore, counting the routine as waiting if it has to block.  Returns
+// the blocker's error if the unit was not taken.
+func (buf *buffer[T]) take(semaphore semaphores.PrioritySemaphore, waiting *int32, priority int, block blocker) error {
+	priority = buf.priority(priority)
+	if semaphore.TryTakePriority(priority, 0) {
+		return nil
+	}
+
+	atomic.AddInt32(waiting, 1)
+	defer atomic.AddInt32(waiting, -1)
+	defer buf.watchdog.Begin("buffer")()
+	return block(semaphore, priority)
+}
+
+// Append a message to the buffer after a slot has been taken.
+func (buf *buffer[T]) push(message T) {
+	buf.lock.Lock()
+	var tail = (buf.head + buf.pending) % int32(len(buf.messages))
+	buf.messages[tail] = message
+	buf.pending++
+	buf.lock.Unlock()
+	buf.available.Give()
+}
+
+// Remove the oldest message from the buffer after a message has been taken.
+func (buf *buffer[T]) pop() T {
+	var zero T
+	buf.lock.Lock()
+	var message = buf.messages[buf.head]
+	buf.messages[buf.head] = zero
+	buf.head = (buf.head + 1) % int32(len(buf.messages))
+	buf.pending--
+	buf.lock.Unlock()
+	buf.slots.Give()
+	return message
+}
+
+func (buf *buffer[T]) send(priority int, message T, block blocker) error {
+	if err := buf.take(buf.slots, &buf.waitingSenders, priority, block); err != nil {
+		return err
+	}
+	buf.push(message)
+	return nil
+}
+
+func (buf *buffer[T]) receive(priority int, block blocker) (T, error) {
+	if err := buf.take(buf.available, &buf.waitingReceivers, priority, block); err != nil {
+		var zero T
+		return zero, err
+	}
+	return buf.pop(), nil
+}
+
+func (buf *buffer[T]) Send(message T) {
+	buf.SendPriority(semaphores.DefaultPriority, message)
+}
+
+func (buf *buffer[T]) TimedSend(message T, timeout time.Duration) bool {
+	return buf.TimedSendPriority(semaphores.DefaultPriority, message, timeout)
+}
+
+func (buf *buffer[T]) SendContext(ctx context.Context, message T) error {
+	return buf.send(semaphores.DefaultPriority, message, untilDone(ctx))
+}
+
+func (buf *buffer[T]) SendPriority(priority int, message T) {
+	buf.send(priority, message, untilDone(context.Background()))
+}
+
+func (buf *buffer[T]) TimedSendPriority(priority int, message T, timeout time.Duration) bool {
+	return buf.send(priority, message, untilTimeout(timeout)) == nil
+}
+
+func (buf *buffer[T]) SendTask(task tasks.Task, message T) {
+	if buf.discipline == Fifo {
+		buf.Send(message)
+		return
+	}
+	buf.send(task.Priority(), message, untilTaken(task))
+}
+
+func (buf *buffer[T]) Receive() T {
+	return buf.ReceivePriority(semaphores.DefaultPriority)
+}
+
+func (buf *buffer[T]) TimedReceive(timeout time.Duration) (T, bool) {
+	return buf.TimedReceivePriority(semaphores.DefaultPriority, timeout)
+}
+
+func (buf *buffer[T]) ReceiveContext(ctx context.Context) (T, error) {
+	return buf.receive(semaphores.DefaultPriority, untilDone(ctx))
+}
+
+func (buf *buffer[T]) ReceivePriority(priority int) T {
+	var message, _ = buf.receive(priority, untilDone(context.Background()))
+	return message
+}
+
+func (buf *buffer[T]) TimedReceivePriority(priority int, timeout time.Duration) (T, bool) {
+	var message, err = buf.receive(priority, untilTimeout(timeout))
+	return message, err == nil
+}
+
+func (buf *buffer[T]) ReceiveTask(task tasks.Task) T {
+	if buf.discipline == Fifo {
+		return buf.Receive()
+	}
+	var message, _ = buf.receive(task.Priority(), untilTaken(task))
+	return message
+}
+
+func (buf *buffer[T]) Pending() int32 {
+	buf.lock.Lock()
+	defer buf.lock.Unlock()
+	return buf.pending
+}
+
+func (buf *buffer[T]) Capacity() int32 {
+	return int32(len(buf.messages))
+}
+
+func (buf *buffer[T]) WaitingSenders() int32 {
+	return atomic.LoadInt32(&buf.waitingSenders)
+}
+
+func (buf *buffer[T]) WaitingReceivers() int32 {
+	return atomic.LoadInt32(&buf.waitingReceivers)
+}
+
+func (buf *buffer[T]) Discipline() QueuingDiscipline {
+	return buf.discipline
+}
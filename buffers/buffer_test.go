@@ -0,0 +1,186 @@
+// Copyright 2026 Jeffrey Bester <jbester@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and
+// to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of
+// the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package buffers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"bitbucket.org/jbester/sync/clock"
+	"bitbucket.org/jbester/sync/tasks"
+	"bitbucket.org/jbester/sync/watchdog"
+	"github.com/stretchr/testify/assert"
+)
+
+// wait until the given number of routines are blocked receiving
+func waitForReceivers[T any](buf Buffer[T], count int32) {
+	for buf.WaitingReceivers() != count {
+		<-time.After(time.Millisecond)
+	}
+}
+
+// wait until the given number of routines are blocked sending
+func waitForSenders[T any](buf Buffer[T], count int32) {
+	for buf.WaitingSenders() != count {
+		<-time.After(time.Millisecond)
+	}
+}
+
+func Test_SendReceiveInOrder(t *testing.T) {
+	var buf = MakeBuffer[string](3, Fifo)
+	buf.Send("a")
+	buf.Send("b")
+	buf.Send("c")
+	assert.Equal(t, int32(3), buf.Pending())
+	assert.Equal(t, "a", buf.Receive())
+	buf.Send("d")
+	assert.Equal(t, "b", buf.Receive())
+	assert.Equal(t, "c", buf.Receive())
+	assert.Equal(t, "d", buf.Receive())
+	assert.Equal(t, int32(0), buf.Pending())
+}
+
+func Test_SendFullTimeout(t *testing.T) {
+	var buf = MakeBuffer[int](1, Fifo)
+	assert.True(t, buf.TimedSend(1, time.Millisecond))
+	assert.False(t, buf.TimedSend(2, time.Millisecond))
+	assert.Equal(t, int32(1), buf.Pending())
+	assert.Equal(t, int32(0), buf.WaitingSenders())
+}
+
+func Test_ReceiveEmptyTimeout(t *testing.T) {
+	var buf = MakeBuffer[int](1, Fifo)
+	var message, ok = buf.TimedReceive(time.Millisecond)
+	assert.False(t, ok)
+	assert.Equal(t, 0, message)
+	assert.Equal(t, int32(0), buf.WaitingReceivers())
+}
+
+func Test_BlockedSenderReleased(t *testing.T) {
+	var buf = MakeBuffer[int](1, Fifo)
+	buf.Send(1)
+	var done = make(chan struct{})
+	go func() {
+		buf.Send(2)
+		close(done)
+	}()
+	waitForSenders(buf, 1)
+	assert.Equal(t, 1, buf.Receive())
+	<-done
+	assert.Equal(t, 2, buf.Receive())
+}
+
+func Test_ReceiveContextCancelled(t *testing.T) {
+	var buf = MakeBuffer[int](1, Fifo)
+	var ctx, cancel = context.WithCancel(context.Background())
+	var result = make(chan error)
+	go func() {
+		var _, err = buf.ReceiveContext(ctx)
+		result <- err
+	}()
+	waitForReceivers(buf, 1)
+	cancel()
+	assert.Equal(t, context.Canceled, <-result)
+	assert.Equal(t, int32(0), buf.WaitingReceivers())
+}
+
+func Test_FifoIgnoresPriority(t *testing.T) {
+	var buf = MakeBuffer[int](1, Fifo)
+	var order = make(chan int, 2)
+	for i, priority := range []int{1, 10} {
+		var prio = priority
+		go func() {
+			order <- prio + buf.ReceivePriority(prio)
+		}()
+		waitForReceivers(buf, int32(i+1))
+	}
+	buf.Send(0)
+	assert.Equal(t, 1, <-order)
+	buf.Send(0)
+	assert.Equal(t, 10, <-order)
+}
+
+func Test_PriorityReceivers(t *testing.T) {
+	var buf = MakeBuffer[int](1, Priority)
+	assert.Equal(t, Priority, buf.Discipline())
+	var order = make(chan int, 3)
+	for i, priority := range []int{1, 10, 5} {
+		var prio = priority
+		go func() {
+			order <- prio + buf.ReceivePriority(prio)
+		}()
+		waitForReceivers(buf, int32(i+1))
+	}
+	for _, expected := range []int{10, 5, 1} {
+		buf.Send(0)
+		assert.Equal(t, expected, <-order)
+	}
+}
+
+func Test_TimedReceiveFakeClock(t *testing.T) {
+	var fake = clock.MakeFakeClock(time.Now())
+	var buf = MakeBuffer[int](1, Fifo, WithClock(fake))
+	var result = make(chan bool)
+	go func() {
+		var _, ok = buf.TimedReceive(time.Hour)
+		result <- ok
+	}()
+	waitForReceivers(buf, 1)
+	fake.WaitForTimers(1)
+	fake.Advance(time.Hour)
+	assert.False(t, <-result)
+	assert.Equal(t, int32(0), buf.WaitingReceivers())
+}
+
+func Test_ReceiveTask(t *testing.T) {
+	var buf = MakeBuffer[int](1, Priority)
+	var order = make(chan int, 2)
+	var low, high = tasks.MakeTask(1), tasks.MakeTask(5)
+	for i, task := range []tasks.Task{low, high} {
+		var receiver = task
+		var id = i
+		go func() {
+			buf.ReceiveTask(receiver)
+			order <- id
+		}()
+		waitForReceivers(buf, int32(i+1))
+	}
+	// the low priority receiver inherits a priority above the other receiver
+	low.SetPriority(10)
+	buf.SendTask(tasks.MakeTask(0), 1)
+	assert.Equal(t, 0, <-order)
+	buf.Send(2)
+	assert.Equal(t, 1, <-order)
+}
+
+// Verify a blocked wait is recorded by the watchdog
+func Test_BufferWatchdog(t *testing.T) {
+	var dog = watchdog.MakeWatchdog(time.Hour)
+	defer dog.Stop()
+	var buf = MakeBuffer[int](1, Fifo, WithWatchdog(dog))
+	var done = make(chan struct{})
+	go func() {
+		buf.Receive()
+		close(done)
+	}()
+	for len(dog.Waits()) == 0 {
+		<-time.After(time.Millisecond)
+	}
+	assert.Equal(t, "buffer", dog.Waits()[0].Kind)
+	buf.Send(1)
+	<-done
+	assert.Empty(t, dog.Waits())
+}
@@ -0,0 +1,141 @@
+// Copyright 2026 Jeffrey Bester <jbester@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and
+// to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of
+// the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// A FakeClock is a Clock whose time only changes when advanced.  Timers fire when the clock is
+// advanced to or past their deadline.
+type FakeClock interface {
+	Clock
+
+	// Advance moves the time forward by the duration, firing any timers that expire.
+	Advance(d time.Duration)
+
+	// Timers returns the number of timers waiting to fire.
+	Timers() int
+
+	// WaitForTimers blocks until at least count timers are waiting to fire.  Tests use it to
+	// know a routine has started a timed wait before advancing the clock.
+	WaitForTimers(count int)
+}
+
+type fakeTimer struct {
+	clock    *fakeClock
+	deadline time.Time
+	ch       chan time.Time
+}
+
+type fakeClock struct {
+	lock    *sync.Mutex
+	changed *sync.Cond
+	now     time.Time
+	timers  []*fakeTimer
+}
+
+// Create a fake clock set to the start time.
+func MakeFakeClock(start time.Time) FakeClock {
+	var lock = &sync.Mutex{}
+	return &fakeClock{
+		lock:    lock,
+		changed: sync.NewCond(lock),
+		now:     start,
+	}
+}
+
+func (clock *fakeClock) Now() time.Time {
+	clock.lock.Lock()
+	defer clock.lock.Unlock()
+	return clock.now
+}
+
+func (clock *fakeClock) Since(t time.Time) time.Duration {
+	return clock.Now().Sub(t)
+}
+
+func (clock *fakeClock) NewTimer(d time.Duration) Timer {
+	clock.lock.Lock()
+	defer clock.lock.Unlock()
+	var timer = &fakeTimer{clock: clock, deadline: clock.now.Add(d), ch: make(chan time.Time, 1)}
+	if d <= 0 {
+		timer.ch <- clock.now
+		return timer
+	}
+	clock.timers = append(clock.timers, timer)
+	clock.changed.Broadcast()
+	return timer
+}
+
+func (clock *fakeClock) Advance(d time.Duration) {
+	clock.lock.Lock()
+	defer clock.lock.Unlock()
+	clock.now = clock.now.Add(d)
+
+	// fire expired timers in deadline order
+	sort.SliceStable(clock.timers, func(i, j int) bool {
+		return clock.timers[i].deadline.Before(clock.timers[j].deadline)
+	})
+	var fired = 0
+	for _, timer := range clock.timers {
+		if timer.deadline.After(clock.now) {
+			break
+		}
+		timer.ch <- clock.now
+		fired++
+	}
+	clock.timers = clock.timers[fired:]
+	clock.changed.Broadcast()
+}
+
+func (clock *fakeClock) Timers() int {
+	clock.lock.Lock()
+	defer clock.lock.Unlock()
+	return len(clock.timers)
+}
+
+func (clock *fakeClock) WaitForTimers(count int) {
+	clock.lock.Lock()
+	defer clock.lock.Unlock()
+	for len(clock.timers) < count {
+		clock.changed.Wait()
+	}
+}
+
+// Remove the timer from the pending timers.  Returns false if it was not pending.
+func (clock *fakeClock) remove(timer *fakeTimer) bool {
+	clock.lock.Lock()
+	defer clock.lock.Unlock()
+	for i, pending := range clock.timers {
+		if pending == timer {
+			clock.timers = append(clock.timers[:i], clock.timers[i+1:]...)
+			clock.changed.Broadcast()
+			return true
+		}
+	}
+	return false
+}
+
+func (timer *fakeTimer) C() <-chan time.Time {
+	return timer.ch
+}
+
+func (timer *fakeTimer) Stop() bool {
+	return timer.clock.remove(timer)
+}
@@ -0,0 +1,79 @@
+// Copyright 2026 Jeffrey Bester <jbester@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and
+// to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of
+// the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var epoch = time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func Test_FakeNow(t *testing.T) {
+	var clock = MakeFakeClock(epoch)
+	assert.Equal(t, epoch, clock.Now())
+	clock.Advance(time.Hour)
+	assert.Equal(t, epoch.Add(time.Hour), clock.Now())
+	assert.Equal(t, time.Hour, clock.Since(epoch))
+}
+
+func Test_FakeTimerFires(t *testing.T) {
+	var clock = MakeFakeClock(epoch)
+	var timer = clock.NewTimer(time.Second)
+	assert.Equal(t, 1, clock.Timers())
+	clock.Advance(time.Millisecond * 999)
+	select {
+	case <-timer.C():
+		assert.Fail(t, "timer fired early")
+	default:
+	}
+	clock.Advance(time.Millisecond)
+	assert.Equal(t, epoch.Add(time.Second), <-timer.C())
+	assert.Equal(t, 0, clock.Timers())
+	assert.False(t, timer.Stop())
+}
+
+func Test_FakeTimerStop(t *testing.T) {
+	var clock = MakeFakeClock(epoch)
+	var timer = clock.NewTimer(time.Second)
+	assert.True(t, timer.Stop())
+	assert.Equal(t, 0, clock.Timers())
+	clock.Advance(time.Hour)
+	select {
+	case <-timer.C():
+		assert.Fail(t, "stopped timer fired")
+	default:
+	}
+}
+
+func Test_FakeTimerZero(t *testing.T) {
+	var clock = MakeFakeClock(epoch)
+	var timer = clock.NewTimer(0)
+	assert.Equal(t, epoch, <-timer.C())
+	assert.Equal(t, 0, clock.Timers())
+}
+
+func Test_FakeWaitForTimers(t *testing.T) {
+	var clock = MakeFakeClock(epoch)
+	var fired = make(chan time.Time)
+	go func() {
+		fired <- <-clock.NewTimer(time.Minute).C()
+	}()
+	clock.WaitForTimers(1)
+	clock.Advance(time.Minute)
+	assert.Equal(t, epoch.Add(time.Minute), <-fired)
+}
@@ -0,0 +1,88 @@
+// Copyright 2026 Jeffrey Bester <jbester@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and
+// to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of
+// the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package events
+
+import (
+	"context"
+	"time"
+
+	"bitbucket.org/jbester/sync/metrics"
+	"bitbucket.org/jbester/sync/semaphores"
+	"bitbucket.org/jbester/sync/startgroup"
+)
+
+type autoResetEvent struct {
+	signal   semaphores.Semaphore
+	observed startgroup.StartGroup
+}
+
+// Creates an auto-reset event for use by any routine.  Upon creation the event is set to the unset state.
+//
+// Setting an auto-reset event releases exactly one waiting routine and the event returns to the unset
+// state.  If no routine is waiting the event remains set until a single routine waits on it.  Waiting
+// routines are released in arrival order.
+//
+// The channel returned by Done is closed when the event is set but receiving from it does not
+// reset the event; use Wait or TimedWait to take the signal.
+func MakeAutoResetEvent(opts ...Option) Event {
+	var options = makeOptions(opts)
+	var signal = semaphores.MakeFairBinarySemaphore(false,
+		semaphores.WithClock(options.clock), semaphores.WithWatchdog(options.watchdog))
+	return &autoResetEvent{
+		signal:   signal,
+		observed: startgroup.MakeStartGroup(),
+	}
+}
+
+func (evt *autoResetEvent) Set() bool {
+	var ok = evt.signal.Give()
+	if ok {
+		evt.observed.Release()
+	}
+	return ok
+}
+
+func (evt *autoResetEvent) IsSet() bool {
+	return evt.signal.IsFull()
+}
+
+func (evt *autoResetEvent) Reset() bool {
+	return evt.signal.TakeNoWait()
+}
+
+func (evt *autoResetEvent) Wait() {
+	evt.signal.Take()
+}
+
+func (evt *autoResetEvent) TimedWait(timeout time.Duration) bool {
+	return evt.signal.TryTake(timeout)
+}
+
+func (evt *autoResetEvent) WaitContext(ctx context.Context) error {
+	return evt.signal.TakeContext(ctx)
+}
+
+func (evt *autoResetEvent) Done() <-chan struct{} {
+	var done = evt.observed.Done()
+	if evt.IsSet() {
+		return closedChannel
+	}
+	return done
+}
+
+func (evt *autoResetEvent) Stats() metrics.Stats {
+	return evt.signal.Stats()
+}
@@ -13,20 +13,30 @@
 // OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
 // OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
 
-// Package events provides a single synchronization primitive - the Event.  The event is used to notify that
+// Package events provides the Event synchronization primitive.  The event is used to notify that
 // a condition has occurred to routines.  Each event has two states - set and unset.  Set state indicates the condition has occurred.
 //
 // Multiple routines can wait on a condition.   _All_ routines unblock once the event is set to the set state.
-// A routine that waits on an event that is already set will not block.
+// A routine that waits on an event that is already set will not block.  An auto-reset event instead
+// releases a single routine when set and returns to the unset state.
 //
 // The event primitive is similar to the event in the pSOS or ARINC 653 APIs.
+//
+// A TypedEvent carries a value that is delivered to every routine waiting on the event.
+//
+// The package also provides the EventGroup, a set of event flags that routines can wait on
+// for any or all of a combination of flags, similar to the pSOS ev_send and ev_receive services.
 package events
 
 import (
+	"context"
 	"sync/atomic"
 	"time"
 
+	"bitbucket.org/jbester/sync/clock"
+	"bitbucket.org/jbester/sync/metrics"
 	"bitbucket.org/jbester/sync/startgroup"
+	"bitbucket.org/jbester/sync/watchdog"
 )
 
 // Multiple routines can wait on a condition.   _All_ routines unblock once the event is set to the set state.
@@ -52,21 +62,54 @@ type Event interface {
 	//  Wait for the event to be in the set state up to the given timeout.  Any routine that attempts to wait
 	//  on an event already in the set state will not block.
 	TimedWait(timeout time.Duration) bool
+
+	//  Wait for the event to be in the set state until the context is done.  Returns the context's
+	//  error if the context finished first.
+	WaitContext(ctx context.Context) error
+
+	//  Done returns a channel that is closed when the event is in the set state, for use in a
+	//  select statement.  A channel returned while the event is unset is closed by the next Set.
+	Done() <-chan struct{}
+
+	//  Returns a snapshot of the event's statistics.  Waits that return because the event is set
+	//  are counted as acquisitions.
+	Stats() metrics.Stats
+}
+
+// A channel that is always closed, returned by Done while an event is set.
+var closedChannel = make(chan struct{})
+
+func init() {
+	close(closedChannel)
 }
 
 type event struct {
 	state      int32
 	notifyList startgroup.StartGroup
+	clock      clock.Clock
+	stats      *metrics.Collector
+	watchdog   watchdog.Watchdog
 }
 
 // Creates an event object for use by any routine.  Upon creation the event is set to the unset state.
-func MakeEvent() Event {
+func MakeEvent(opts ...Option) Event {
+	var options = makeOptions(opts)
 	return &event{
 		state:      0,
 		notifyList: startgroup.MakeStartGroup(),
+		clock:      options.clock,
+		stats:      metrics.MakeCollector(options.clock),
+		watchdog:   options.watchdog,
 	}
 }
 
+// Creates an event registered under the name so its statistics are exported.
+func MakeNamedEvent(name string, opts ...Option) Event {
+	var evt = MakeEvent(opts...)
+	makeOptions(opts).registry.Register(name, evt)
+	return evt
+}
+
 func (evt *event) Set() bool {
 	var ok = atomic.CompareAndSwapInt32(&evt.state, 0, 1)
 	if ok {
@@ -83,18 +126,62 @@ func (evt *event) Reset() bool {
 	return atomic.CompareAndSwapInt32(&evt.state, 1, 0)
 }
 
+func (evt *event) Done() <-chan struct{} {
+	// take the release channel before checking the state so a set in between closes it
+	var done = evt.notifyList.Done()
+	if evt.IsSet() {
+		return closedChannel
+	}
+	return done
+}
+
 func (evt *event) Wait() {
 	if evt.IsSet() {
+		evt.stats.Acquired()
 		return
-	} else {
-		evt.notifyList.Wait()
 	}
+	var wait = evt.stats.BeginWait()
+	defer evt.watchdog.Begin("event")()
+	<-evt.Done()
+	wait.End(metrics.Acquired)
 }
 
 func (evt *event) TimedWait(timeout time.Duration) bool {
 	if evt.IsSet() {
+		evt.stats.Acquired()
 		return true
-	} else {
-		return evt.notifyList.TimedWait(timeout)
 	}
+	var wait = evt.stats.BeginWait()
+	defer evt.watchdog.Begin("event")()
+	var timer = evt.clock.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-evt.Done():
+		wait.End(metrics.Acquired)
+		return true
+	case <-timer.C():
+		wait.End(metrics.TimedOut)
+		return false
+	}
+}
+
+func (evt *event) WaitContext(ctx context.Context) error {
+	if evt.IsSet() {
+		evt.stats.Acquired()
+		return nil
+	}
+	var wait = evt.stats.BeginWait()
+	defer evt.watchdog.Begin("event")()
+	select {
+	case <-evt.Done():
+		wait.End(metrics.Acquired)
+		return nil
+	case <-ctx.Done():
+		wait.End(metrics.Cancelled)
+		return ctx.Err()
+	}
+}
+
+func (evt *event) Stats() metrics.Stats {
+	return evt.stats.Stats()
 }
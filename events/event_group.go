@@ -0,0 +1,190 @@
+// Copyright 2026 Jeffrey Bester <jbester@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and
+// to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of
+// the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package events
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"bitbucket.org/jbester/sync/clock"
+	"bitbucket.org/jbester/sync/watchdog"
+)
+
+// WaitMode selects how an EventGroup receive matches the requested flags.
+type WaitMode int
+
+const (
+	// Wait for any of the requested flags to be set.
+	WaitAny WaitMode = iota
+
+	// Wait for all of the requested flags to be set.
+	WaitAll
+)
+
+// An EventGroup is a set of 64 event flags, modelled on the pSOS ev_send/ev_receive services.
+// Routines send flags to the group and receive when any or all of a requested set of flags
+// are set.  Receiving may optionally consume (clear) the matched flags.
+//
+// Waiting routines are satisfied in arrival order so a consuming receiver that arrived
+// first takes the flags before later receivers are examined.
+type EventGroup interface {
+	//  Send sets the flags in mask.  Waiting routines whose conditions are satisfied are
+	//  released.
+	Send(mask uint64)
+
+	//  Clear resets the flags in mask.
+	Clear(mask uint64)
+
+	//  Flags returns the flags currently set.
+	Flags() uint64
+
+	//  Receive waits until the flags in mask satisfy the mode.  If consume is true the
+	//  matched flags are cleared.  Returns the matched flags.  As with pSOS ev_receive, an
+	//  empty mask returns the flags currently set without waiting or clearing any flags.
+	Receive(mask uint64, mode WaitMode, consume bool) uint64
+
+	//  TimedReceive waits until the flags in mask satisfy the mode or the timeout occurs.  If consume
+	//  is true the matched flags are cleared.  Returns the matched flags and true if the
+	//  condition was satisfied.  An empty mask returns the flags currently set and true.
+	TimedReceive(mask uint64, mode WaitMode, consume bool, timeout time.Duration) (uint64, bool)
+}
+
+type groupWaiter struct {
+	mask    uint64
+	mode    WaitMode
+	consume bool
+	matched uint64
+	ready   chan empty
+	element *list.Element
+	granted bool
+}
+
+type eventGroup struct {
+	lock     *sync.Mutex
+	flags    uint64
+	waiters  *list.List
+	clock    clock.Clock
+	watchdog watchdog.Watchdog
+}
+
+type empty struct{}
+
+// Creates an event group for use by any routine.  Upon creation all flags are clear.
+func MakeEventGroup(opts ...Option) EventGroup {
+	var options = makeOptions(opts)
+	return &eventGroup{
+		lock:     &sync.Mutex{},
+		waiters:  list.New(),
+		clock:    options.clock,
+		watchdog: options.watchdog,
+	}
+}
+
+// Returns the matched flags and true if the flags satisfy the mask under the mode.
+func matchFlags(flags uint64, mask uint64, mode WaitMode) (uint64, bool) {
+	var matched = flags & mask
+	if mode == WaitAll {
+		return matched, matched == mask
+	}
+	return matched, matched != 0
+}
+
+// Try to satisfy a receive from the current flags.  Must be called with the lock held.
+func (group *eventGroup) tryReceive(mask uint64, mode WaitMode, consume bool) (uint64, bool) {
+	var matched, ok = matchFlags(group.flags, mask, mode)
+	if ok && consume {
+		group.flags &^= matched
+	}
+	return matched, ok
+}
+
+// Release waiting routines satisfied by the current flags.  Must be called with the lock held.
+func (group *eventGroup) dispatch() {
+	var next *list.Element
+	for e := group.waiters.Front(); e != nil; e = next {
+		next = e.Next()
+		var waiter = e.Value.(*groupWaiter)
+		if matched, ok := group.tryReceive(waiter.mask, waiter.mode, waiter.consume); ok {
+			group.waiters.Remove(e)
+			waiter.matched = matched
+			waiter.granted = true
+			close(waiter.ready)
+		}
+	}
+}
+
+func (group *eventGroup) receive(mask uint64, mode WaitMode, consume bool, expired <-chan time.Time) (uint64, bool) {
+	group.lock.Lock()
+	// an empty request queries the current flags
+	if mask == 0 {
+		var flags = group.flags
+		group.lock.Unlock()
+		return flags, true
+	}
+	if matched, ok := group.tryReceive(mask, mode, consume); ok {
+		group.lock.Unlock()
+		return matched, true
+	}
+	var waiter = &groupWaiter{mask: mask, mode: mode, consume: consume, ready: make(chan empty)}
+	waiter.element = group.waiters.PushBack(waiter)
+	group.lock.Unlock()
+
+	defer group.watchdog.Begin("event group")()
+	select {
+	case <-waiter.ready:
+		return waiter.matched, true
+	case <-expired:
+	}
+
+	group.lock.Lock()
+	defer group.lock.Unlock()
+	if waiter.granted {
+		return waiter.matched, true
+	}
+	group.waiters.Remove(waiter.element)
+	return group.flags & mask, false
+}
+
+func (group *eventGroup) Send(mask uint64) {
+	group.lock.Lock()
+	defer group.lock.Unlock()
+	group.flags |= mask
+	group.dispatch()
+}
+
+func (group *eventGroup) Clear(mask uint64) {
+	group.lock.Lock()
+	defer group.lock.Unlock()
+	group.flags &^= mask
+}
+
+func (group *eventGroup) Flags() uint64 {
+	group.lock.Lock()
+	defer group.lock.Unlock()
+	return group.flags
+}
+
+func (group *eventGroup) Receive(mask uint64, mode WaitMode, consume bool) uint64 {
+	var matched, _ = group.receive(mask, mode, consume, nil)
+	return matched
+}
+
+func (group *eventGroup) TimedReceive(mask uint64, mode WaitMode, consume bool, timeout time.Duration) (uint64, bool) {
+	var timer = group.clock.NewTimer(timeout)
+	defer timer.Stop()
+	return group.receive(mask, mode, consume, timer.C())
+}
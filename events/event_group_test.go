@@ -0,0 +1,158 @@
+// Copyright 2026 Jeffrey Bester <jbester@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and
+// to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of
+// the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package events
+
+import (
+	"testing"
+	"time"
+
+	"bitbucket.org/jbester/sync/clock"
+	"bitbucket.org/jbester/sync/watchdog"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	flagA uint64 = 1 << iota
+	flagB
+	flagC
+)
+
+func Test_GroupReceiveAlreadySet(t *testing.T) {
+	var group = MakeEventGroup()
+	group.Send(flagA | flagB)
+	assert.Equal(t, flagA, group.Receive(flagA|flagC, WaitAny, false))
+	assert.Equal(t, flagA|flagB, group.Flags())
+}
+
+func Test_GroupReceiveConsume(t *testing.T) {
+	var group = MakeEventGroup()
+	group.Send(flagA | flagB)
+	assert.Equal(t, flagA|flagB, group.Receive(flagA|flagB, WaitAll, true))
+	assert.Equal(t, uint64(0), group.Flags())
+}
+
+func Test_GroupClear(t *testing.T) {
+	var group = MakeEventGroup()
+	group.Send(flagA | flagB)
+	group.Clear(flagA)
+	assert.Equal(t, flagB, group.Flags())
+}
+
+func Test_GroupWaitAny(t *testing.T) {
+	var group = MakeEventGroup()
+	var result = make(chan uint64)
+	go func() {
+		result <- group.Receive(flagA|flagB, WaitAny, false)
+	}()
+	<-time.After(time.Millisecond)
+	group.Send(flagC)
+	group.Send(flagB)
+	assert.Equal(t, flagB, <-result)
+}
+
+func Test_GroupWaitAll(t *testing.T) {
+	var group = MakeEventGroup()
+	var result = make(chan uint64)
+	go func() {
+		result <- group.Receive(flagA|flagB, WaitAll, true)
+	}()
+	group.Send(flagA)
+	select {
+	case <-result:
+		assert.Fail(t, "receive completed with only some flags set")
+	case <-time.After(time.Millisecond * 10):
+	}
+	group.Send(flagB | flagC)
+	assert.Equal(t, flagA|flagB, <-result)
+	// only the matched flags are consumed
+	assert.Equal(t, flagC, group.Flags())
+}
+
+func Test_GroupTimedReceiveTimeout(t *testing.T) {
+	var group = MakeEventGroup()
+	group.Send(flagA)
+	var matched, ok = group.TimedReceive(flagA|flagB, WaitAll, true, time.Millisecond)
+	assert.False(t, ok)
+	assert.Equal(t, flagA, matched)
+	// nothing is consumed on timeout
+	assert.Equal(t, flagA, group.Flags())
+}
+
+func Test_GroupConsumeInArrivalOrder(t *testing.T) {
+	var group = MakeEventGroup()
+	var first = make(chan bool)
+	var second = make(chan bool)
+	go func() {
+		var _, ok = group.TimedReceive(flagA, WaitAny, true, time.Second)
+		first <- ok
+	}()
+	<-time.After(time.Millisecond * 5)
+	go func() {
+		var _, ok = group.TimedReceive(flagA, WaitAny, true, time.Millisecond*20)
+		second <- ok
+	}()
+	<-time.After(time.Millisecond * 5)
+	group.Send(flagA)
+	assert.True(t, <-first)
+	assert.False(t, <-second)
+}
+
+func Test_GroupTimedReceiveFakeClock(t *testing.T) {
+	var fake = clock.MakeFakeClock(time.Now())
+	var group = MakeEventGroup(WithClock(fake))
+	var result = make(chan bool)
+	go func() {
+		var _, ok = group.TimedReceive(flagA, WaitAny, false, time.Hour)
+		result <- ok
+	}()
+	fake.WaitForTimers(1)
+	fake.Advance(time.Hour)
+	assert.False(t, <-result)
+}
+
+func Test_GroupReceiveEmptyMask(t *testing.T) {
+	var group = MakeEventGroup()
+	for _, mode := range []WaitMode{WaitAny, WaitAll} {
+		assert.Equal(t, uint64(0), group.Receive(0, mode, true))
+	}
+	group.Send(flagA | flagC)
+	for _, mode := range []WaitMode{WaitAny, WaitAll} {
+		// the current flags are returned and none are consumed
+		assert.Equal(t, flagA|flagC, group.Receive(0, mode, true))
+		var flags, ok = group.TimedReceive(0, mode, true, time.Millisecond)
+		assert.True(t, ok)
+		assert.Equal(t, flagA|flagC, flags)
+	}
+	assert.Equal(t, flagA|flagC, group.Flags())
+}
+
+// Verify a blocked wait is recorded by the watchdog
+func Test_EventGroupWatchdog(t *testing.T) {
+	var dog = watchdog.MakeWatchdog(time.Hour)
+	defer dog.Stop()
+	var group = MakeEventGroup(WithWatchdog(dog))
+	var done = make(chan struct{})
+	go func() {
+		group.Receive(1, WaitAny, true)
+		close(done)
+	}()
+	for len(dog.Waits()) == 0 {
+		<-time.After(time.Millisecond)
+	}
+	assert.Equal(t, "event group", dog.Waits()[0].Kind)
+	group.Send(1)
+	<-done
+	assert.Empty(t, dog.Waits())
+}
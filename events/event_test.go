@@ -15,11 +15,14 @@
 package events
 
 import (
+	"context"
 	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"bitbucket.org/jbester/sync/clock"
+	"bitbucket.org/jbester/sync/metrics"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 )
@@ -39,8 +42,8 @@ type callback func()
 
 //  Spawn a routine to wait on the event
 func (suite *TestEventSuite) asyncWait(onWaitComplete callback) {
+	suite.waitGroup.Add(1)
 	go func() {
-		suite.waitGroup.Add(1)
 		defer suite.waitGroup.Done()
 		suite.evt.Wait()
 		onWaitComplete()
@@ -87,6 +90,7 @@ func (suite *TestEventSuite) Test_WaitWhenAlreadySet() {
 	suite.asyncWait(func() {
 		eventReceived = true
 	})
+	suite.waitGroup.Wait()
 	assert.True(suite.T(), eventReceived)
 }
 
@@ -104,10 +108,115 @@ func (suite *TestEventSuite) Test_WaitMultiple() {
 	assert.Equal(suite.T(), int32(2), eventCount)
 }
 
+//  Test that the done channel is closed when the event is set
+func (suite *TestEventSuite) Test_Done() {
+	var done = suite.evt.Done()
+	select {
+	case <-done:
+		assert.Fail(suite.T(), "done closed before set")
+	default:
+	}
+	suite.evt.Set()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		assert.Fail(suite.T(), "done not closed by set")
+	}
+	// the event remains set so done is immediately closed
+	<-suite.evt.Done()
+	suite.evt.Reset()
+	select {
+	case <-suite.evt.Done():
+		assert.Fail(suite.T(), "done closed after reset")
+	default:
+	}
+}
+
+//  Test that a timed wait times out when the event is not set
+func (suite *TestEventSuite) Test_TimedWait() {
+	assert.False(suite.T(), suite.evt.TimedWait(time.Millisecond))
+	suite.evt.Set()
+	assert.True(suite.T(), suite.evt.TimedWait(time.Millisecond))
+}
+
+//  Test that a context wait returns when set or when the context is cancelled
+func (suite *TestEventSuite) Test_WaitContext() {
+	var ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(suite.T(), context.Canceled, suite.evt.WaitContext(ctx))
+	suite.evt.Set()
+	assert.NoError(suite.T(), suite.evt.WaitContext(ctx))
+}
+
 func TestEventTestSuite(t *testing.T) {
 	suite.Run(t, new(TestEventSuite))
 }
 
+//  Test that setting an auto-reset event releases a single waiter
+func Test_AutoResetReleasesOne(t *testing.T) {
+	var evt = MakeAutoResetEvent()
+	var released int32 = 0
+	var done = &sync.WaitGroup{}
+	done.Add(2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			defer done.Done()
+			if evt.TimedWait(time.Millisecond * 50) {
+				atomic.AddInt32(&released, 1)
+			}
+		}()
+	}
+	<-time.After(time.Millisecond)
+	assert.True(t, evt.Set())
+	done.Wait()
+	assert.Equal(t, int32(1), released)
+	assert.False(t, evt.IsSet())
+}
+
+//  Test that an auto-reset event stays set until a single routine waits
+func Test_AutoResetRemainsSet(t *testing.T) {
+	var evt = MakeAutoResetEvent()
+	assert.True(t, evt.Set())
+	assert.False(t, evt.Set())
+	assert.True(t, evt.IsSet())
+	evt.Wait()
+	assert.False(t, evt.IsSet())
+	assert.False(t, evt.TimedWait(time.Millisecond))
+}
+
+//  Test that an auto-reset event can be reset before any routine waits
+func Test_AutoResetReset(t *testing.T) {
+	var evt = MakeAutoResetEvent()
+	assert.False(t, evt.Reset())
+	evt.Set()
+	assert.True(t, evt.Reset())
+	assert.False(t, evt.IsSet())
+}
+
+//  Test that resetting an unset auto-reset event does not wait
+func Test_AutoResetResetDoesNotWait(t *testing.T) {
+	var evt = MakeAutoResetEvent()
+	assert.False(t, evt.Reset())
+	assert.False(t, evt.Reset())
+	var stats = evt.Stats()
+	assert.Equal(t, uint64(0), stats.Timeouts)
+	assert.Equal(t, int64(0), stats.MaxWaiters)
+}
+
+//  Test that the done channel of an auto-reset event is closed when set
+func Test_AutoResetDone(t *testing.T) {
+	var evt = MakeAutoResetEvent()
+	var done = evt.Done()
+	evt.Set()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		assert.Fail(t, "done not closed by set")
+	}
+	// receiving from done does not consume the signal
+	assert.True(t, evt.TimedWait(time.Millisecond))
+}
+
 func Benchmark_SetReset(b *testing.B) {
 	var sg = MakeEvent()
 	for n := 0; n < b.N; n++ {
@@ -115,3 +224,47 @@ func Benchmark_SetReset(b *testing.B) {
 		sg.Reset()
 	}
 }
+
+//  Test that a timed wait expires by the injected clock
+func Test_TimedWaitFakeClock(t *testing.T) {
+	var fake = clock.MakeFakeClock(time.Now())
+	var result = make(chan bool)
+	for _, evt := range []Event{MakeEvent(WithClock(fake)), MakeAutoResetEvent(WithClock(fake))} {
+		var target = evt
+		go func() {
+			result <- target.TimedWait(time.Hour)
+		}()
+		fake.WaitForTimers(1)
+		fake.Advance(time.Hour)
+		assert.False(t, <-result)
+	}
+}
+
+//  Test that waits on an event are counted
+func Test_EventStats(t *testing.T) {
+	var fake = clock.MakeFakeClock(time.Now())
+	var evt = MakeEvent(WithClock(fake))
+	var result = make(chan bool)
+	go func() {
+		result <- evt.TimedWait(time.Minute)
+	}()
+	fake.WaitForTimers(1)
+	fake.Advance(time.Second)
+	evt.Set()
+	assert.True(t, <-result)
+	evt.Wait()
+
+	var stats = evt.Stats()
+	assert.Equal(t, uint64(2), stats.Acquisitions)
+	assert.Equal(t, int64(1), stats.MaxWaiters)
+	assert.Equal(t, time.Second, stats.TotalWaitTime)
+}
+
+//  Test that a named event is registered
+func Test_NamedEvent(t *testing.T) {
+	var registry = metrics.MakeRegistry()
+	var evt = MakeNamedEvent("ready", WithRegistry(registry))
+	var source, ok = registry.Lookup("ready")
+	assert.True(t, ok)
+	assert.Equal(t, metrics.Source(evt), source)
+}
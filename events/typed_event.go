@@ -0,0 +1,198 @@
+// Copyright 2026 Jeffrey Bester <jbester@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and
+// to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of
+// the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package events
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"bitbucket.org/jbester/sync/clock"
+	"bitbucket.org/jbester/sync/metrics"
+	"bitbucket.org/jbester/sync/watchdog"
+)
+
+// A TypedEvent is an Event that carries a value of type T.  Setting the event delivers the value to
+// all waiting routines and any routine that waits while the event remains set.
+type TypedEvent[T any] interface {
+	//  Set changes the event to the set state carrying the value.  All the routines waiting on
+	//  the event receive the value and stop waiting.  Setting an event that is already set has no
+	//  effect.  Returns true if the event changed.
+	Set(value T) bool
+
+	//  Resets the event to the unset state, discarding its value.  Returns true if the event
+	//  changed.
+	Reset() bool
+
+	//  Checks if the event is in set state.
+	IsSet() bool
+
+	//  Value returns the value of the event and true if the event is in the set state.
+	Value() (T, bool)
+
+	//  Wait for the event to be in the set state, returning its value.
+	Wait() T
+
+	//  Wait for the event to be in the set state up to the given timeout, returning its value.
+	TimedWait(timeout time.Duration) (T, bool)
+
+	//  Wait for the event to be in the set state until the context is done, returning its value.
+	//  Returns the context's error if the context finished first.
+	WaitContext(ctx context.Context) (T, error)
+
+	//  Returns a snapshot of the event's statistics.  Waits that return because the event is set
+	//  are counted as acquisitions.
+	Stats() metrics.Stats
+}
+
+// The set state of a typed event.  The value is written before the channel is closed.
+type occurrence[T any] struct {
+	done  chan struct{}
+	value T
+}
+
+type typedEvent[T any] struct {
+	lock     *sync.Mutex
+	isSet    bool
+	next     *occurrence[T]
+	clock    clock.Clock
+	stats    *metrics.Collector
+	watchdog watchdog.Watchdog
+}
+
+// Creates a typed event for use by any routine.  Upon creation the event is set to the unset state.
+func MakeTypedEvent[T any](opts ...Option) TypedEvent[T] {
+	var options = makeOptions(opts)
+	return &typedEvent[T]{
+		lock:     &sync.Mutex{},
+		next:     &occurrence[T]{done: make(chan struct{})},
+		clock:    options.clock,
+		stats:    metrics.MakeCollector(options.clock),
+		watchdog: options.watchdog,
+	}
+}
+
+func (evt *typedEvent[T]) current() *occurrence[T] {
+	evt.lock.Lock()
+	defer evt.lock.Unlock()
+	return evt.next
+}
+
+func (evt *typedEvent[T]) Set(value T) bool {
+	evt.lock.Lock()
+	defer evt.lock.Unlock()
+	if evt.isSet {
+		return false
+	}
+	evt.isSet = true
+	evt.next.value = value
+	close(evt.next.done)
+	return true
+}
+
+func (evt *typedEvent[T]) Reset() bool {
+	evt.lock.Lock()
+	defer evt.lock.Unlock()
+	if !evt.isSet {
+		return false
+	}
+	evt.isSet = false
+	evt.next = &occurrence[T]{done: make(chan struct{})}
+	return true
+}
+
+func (evt *typedEvent[T]) IsSet() bool {
+	evt.lock.Lock()
+	defer evt.lock.Unlock()
+	return evt.isSet
+}
+
+func (evt *typedEvent[T]) Value() (T, bool) {
+	evt.lock.Lock()
+	defer evt.lock.Unlock()
+	if !evt.isSet {
+		var zero T
+		return zero, false
+	}
+	return evt.next.value, true
+}
+
+// Returns the occurrence to wait for and true if it has already happened, recording the
+// acquisition.
+func (evt *typedEvent[T]) occurred() (*occurrence[T], bool) {
+	var next = evt.current()
+	select {
+	case <-next.done:
+		evt.stats.Acquired()
+		return next, true
+	default:
+		return next, false
+	}
+}
+
+func (evt *typedEvent[T]) Wait() T {
+	var next, ok = evt.occurred()
+	if ok {
+		return next.value
+	}
+	var wait = evt.stats.BeginWait()
+	defer evt.watchdog.Begin("event")()
+	<-next.done
+	wait.End(metrics.Acquired)
+	return next.value
+}
+
+func (evt *typedEvent[T]) TimedWait(timeout time.Duration) (T, bool) {
+	var next, ok = evt.occurred()
+	if ok {
+		return next.value, true
+	}
+	var wait = evt.stats.BeginWait()
+	defer evt.watchdog.Begin("event")()
+	var timer = evt.clock.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-next.done:
+		wait.End(metrics.Acquired)
+		return next.value, true
+	case <-timer.C():
+		wait.End(metrics.TimedOut)
+		var zero T
+		return zero, false
+	}
+}
+
+func (evt *typedEvent[T]) WaitContext(ctx context.Context) (T, error) {
+	var next, ok = evt.occurred()
+	if ok {
+		return next.value, nil
+	}
+	var wait = evt.stats.BeginWait()
+	defer evt.watchdog.Begin("event")()
+	select {
+	case <-next.done:
+		wait.End(metrics.Acquired)
+		return next.value, nil
+	case <-ctx.Done():
+		wait.End(metrics.Cancelled)
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+func (evt *typedEvent[T]) Stats() metrics.Stats {
+	return evt.stats.Stats()
+}
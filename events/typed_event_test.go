@@ -0,0 +1,114 @@
+// Copyright 2026 Jeffrey Bester <jbester@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and
+// to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of
+// the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package events
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"bitbucket.org/jbester/sync/clock"
+	"bitbucket.org/jbester/sync/watchdog"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test that every waiting routine receives the value
+func Test_TypedWaitValue(t *testing.T) {
+	var evt = MakeTypedEvent[int]()
+	var done = &sync.WaitGroup{}
+	for i := 0; i < 5; i++ {
+		done.Add(1)
+		go func() {
+			defer done.Done()
+			assert.Equal(t, 42, evt.Wait())
+		}()
+	}
+	for evt.Stats().Waiters != 5 {
+		<-time.After(time.Millisecond)
+	}
+	assert.True(t, evt.Set(42))
+	done.Wait()
+	assert.Equal(t, uint64(5), evt.Stats().Acquisitions)
+}
+
+// Test that the value remains until the event is reset
+func Test_TypedSetReset(t *testing.T) {
+	var evt = MakeTypedEvent[string]()
+	var _, ok = evt.Value()
+	assert.False(t, ok)
+	assert.True(t, evt.Set("first"))
+	// setting a set event has no effect
+	assert.False(t, evt.Set("second"))
+	value, ok := evt.Value()
+	assert.True(t, ok)
+	assert.Equal(t, "first", value)
+	assert.Equal(t, "first", evt.Wait())
+
+	assert.True(t, evt.Reset())
+	assert.False(t, evt.Reset())
+	assert.False(t, evt.IsSet())
+	_, ok = evt.TimedWait(time.Millisecond)
+	assert.False(t, ok)
+	evt.Set("third")
+	assert.Equal(t, "third", evt.Wait())
+}
+
+// Test that a context wait returns the value or the context's error
+func Test_TypedWaitContext(t *testing.T) {
+	var evt = MakeTypedEvent[int]()
+	var ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	var _, err = evt.WaitContext(ctx)
+	assert.Equal(t, context.Canceled, err)
+	evt.Set(3)
+	value, err := evt.WaitContext(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, value)
+}
+
+// Test that a timed wait expires by the injected clock
+func Test_TypedTimedWaitFakeClock(t *testing.T) {
+	var fake = clock.MakeFakeClock(time.Now())
+	var evt = MakeTypedEvent[int](WithClock(fake))
+	var result = make(chan bool)
+	go func() {
+		var _, ok = evt.TimedWait(time.Hour)
+		result <- ok
+	}()
+	fake.WaitForTimers(1)
+	fake.Advance(time.Hour)
+	assert.False(t, <-result)
+	assert.Equal(t, uint64(1), evt.Stats().Timeouts)
+}
+
+// Verify a blocked wait is recorded by the watchdog
+func Test_TypedEventWatchdog(t *testing.T) {
+	var dog = watchdog.MakeWatchdog(time.Hour)
+	defer dog.Stop()
+	var evt = MakeTypedEvent[int](WithWatchdog(dog))
+	var done = make(chan struct{})
+	go func() {
+		evt.Wait()
+		close(done)
+	}()
+	for len(dog.Waits()) == 0 {
+		<-time.After(time.Millisecond)
+	}
+	assert.Equal(t, "event", dog.Waits()[0].Kind)
+	evt.Set(1)
+	<-done
+	assert.Empty(t, dog.Waits())
+}
@@ -0,0 +1,64 @@
+// Copyright 2026 Jeffrey Bester <jbester@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and
+// to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of
+// the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package goroutine identifies routines for the ownership checks of the primitives in this
+// module.
+package goroutine
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+)
+
+// Id returns the id of the calling routine, as shown in a goroutine dump.
+func Id() uint64 {
+	var buf = make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+	// the stack begins "goroutine <id> [state]:"
+	buf = bytes.TrimPrefix(buf, []byte("goroutine "))
+	var id uint64 = 0
+	if end := bytes.IndexByte(buf, ' '); end > 0 {
+		id, _ = strconv.ParseUint(string(buf[:end]), 10, 64)
+	}
+	// zero marks an unowned primitive so it must never be returned as an id
+	if id == 0 {
+		panic("goroutine id not found in stack")
+	}
+	return id
+}
+
+// Live returns the ids of every running routine.
+func Live() map[uint64]bool {
+	var buf = make([]byte, 64*1024)
+	for {
+		var n = runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, len(buf)*2)
+	}
+	var live = make(map[uint64]bool)
+	for _, line := range bytes.Split(buf, []byte("\n")) {
+		if bytes.HasPrefix(line, []byte("goroutine ")) {
+			var fields = bytes.Fields(line)
+			if id, err := strconv.ParseUint(string(fields[1]), 10, 64); err == nil {
+				live[id] = true
+			}
+		}
+	}
+	return live
+}
@@ -0,0 +1,47 @@
+// Copyright 2026 Jeffrey Bester <jbester@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and
+// to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of
+// the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package goroutine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Id(t *testing.T) {
+	var id = Id()
+	assert.NotEqual(t, uint64(0), id)
+	assert.Equal(t, id, Id())
+	var other = make(chan uint64)
+	go func() {
+		other <- Id()
+	}()
+	assert.NotEqual(t, id, <-other)
+}
+
+func Test_Live(t *testing.T) {
+	var release = make(chan struct{})
+	var other = make(chan uint64)
+	go func() {
+		other <- Id()
+		<-release
+	}()
+	var id = <-other
+	var live = Live()
+	assert.True(t, live[Id()])
+	assert.True(t, live[id])
+	close(release)
+}
@@ -0,0 +1,157 @@
+// Copyright 2026 Jeffrey Bester <jbester@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and
+// to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of
+// the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package metrics
+
+import (
+	"bufio"
+	"expvar"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// The JSON form of a sample published through expvar.
+type expvarSample struct {
+	Acquisitions     uint64  `json:"acquisitions"`
+	Timeouts         uint64  `json:"timeouts"`
+	Cancellations    uint64  `json:"cancellations"`
+	Waiters          int64   `json:"waiters"`
+	MaxWaiters       int64   `json:"max_waiters"`
+	TotalWaitSeconds float64 `json:"total_wait_seconds"`
+	Count            *int64  `json:"count,omitempty"`
+	Capacity         *int64  `json:"capacity,omitempty"`
+}
+
+// Publish exports the statistics of the registered sources through expvar under the name, as a
+// JSON object keyed by source name.  Sources registered later are included.  Like expvar.Publish
+// it panics if the name is already published.
+func (registry *Registry) Publish(name string) {
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		return registry.expvarSamples()
+	}))
+}
+
+// Returns the JSON form of the registered sources keyed by source name.
+func (registry *Registry) expvarSamples() map[string]expvarSample {
+	var result = make(map[string]expvarSample)
+	for _, sample := range registry.Snapshot() {
+		var value = expvarSample{
+			Acquisitions:     sample.Acquisitions,
+			Timeouts:         sample.Timeouts,
+			Cancellations:    sample.Cancellations,
+			Waiters:          sample.Waiters,
+			MaxWaiters:       sample.MaxWaiters,
+			TotalWaitSeconds: sample.TotalWaitTime.Seconds(),
+		}
+		if sample.HasLevel {
+			var count, capacity = sample.Count, sample.Capacity
+			value.Count, value.Capacity = &count, &capacity
+		}
+		result[sample.Name] = value
+	}
+	return result
+}
+
+// Handler returns an http.Handler serving the statistics of the registered sources in the
+// Prometheus text exposition format.  Each series is labelled with the source name.
+func (registry *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(response http.ResponseWriter, request *http.Request) {
+		response.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		var writer = bufio.NewWriter(response)
+		writePrometheus(writer, registry.Snapshot())
+		writer.Flush()
+	})
+}
+
+// A series written for every sample.
+type series struct {
+	name  string
+	kind  string
+	help  string
+	value func(sample Sample) string
+}
+
+var prometheusSeries = []series{
+	{"sync_acquisitions_total", "counter", "Operations that acquired the primitive or were released by it.",
+		func(sample Sample) string { return formatUint(sample.Acquisitions) }},
+	{"sync_timeouts_total", "counter", "Waits that gave up when their timeout expired.",
+		func(sample Sample) string { return formatUint(sample.Timeouts) }},
+	{"sync_cancellations_total", "counter", "Waits that gave up when their context was cancelled.",
+		func(sample Sample) string { return formatUint(sample.Cancellations) }},
+	{"sync_waiters", "gauge", "Routines currently waiting.",
+		func(sample Sample) string { return strconv.FormatInt(sample.Waiters, 10) }},
+	{"sync_max_waiters", "gauge", "The largest number of routines observed waiting at once.",
+		func(sample Sample) string { return strconv.FormatInt(sample.MaxWaiters, 10) }},
+}
+
+var levelSeries = []series{
+	{"sync_count", "gauge", "Current count of the primitive.",
+		func(sample Sample) string { return strconv.FormatInt(sample.Count, 10) }},
+	{"sync_capacity", "gauge", "Largest count the primitive can hold.",
+		func(sample Sample) string { return strconv.FormatInt(sample.Capacity, 10) }},
+}
+
+func writePrometheus(writer *bufio.Writer, samples []Sample) {
+	for _, s := range prometheusSeries {
+		writeHeader(writer, s.name, s.kind, s.help)
+		for _, sample := range samples {
+			fmt.Fprintf(writer, "%s{name=%s} %s\n", s.name, quoteLabel(sample.Name), s.value(sample))
+		}
+	}
+
+	for _, s := range levelSeries {
+		writeHeader(writer, s.name, s.kind, s.help)
+		for _, sample := range samples {
+			if sample.HasLevel {
+				fmt.Fprintf(writer, "%s{name=%s} %s\n", s.name, quoteLabel(sample.Name), s.value(sample))
+			}
+		}
+	}
+
+	const histogram = "sync_wait_seconds"
+	writeHeader(writer, histogram, "histogram", "Time spent by routines that had to wait.")
+	for _, sample := range samples {
+		var name = quoteLabel(sample.Name)
+		var cumulative uint64 = 0
+		var counts = sample.WaitTimes.Counts
+		for i, bound := range sample.WaitTimes.Bounds {
+			cumulative += counts[i]
+			fmt.Fprintf(writer, "%s_bucket{name=%s,le=\"%s\"} %d\n",
+				histogram, name, strconv.FormatFloat(bound.Seconds(), 'g', -1, 64), cumulative)
+		}
+		cumulative += counts[len(counts)-1]
+		fmt.Fprintf(writer, "%s_bucket{name=%s,le=\"+Inf\"} %d\n", histogram, name, cumulative)
+		fmt.Fprintf(writer, "%s_sum{name=%s} %s\n",
+			histogram, name, strconv.FormatFloat(sample.TotalWaitTime.Seconds(), 'g', -1, 64))
+		fmt.Fprintf(writer, "%s_count{name=%s} %d\n", histogram, name, cumulative)
+	}
+}
+
+func writeHeader(writer *bufio.Writer, name string, kind string, help string) {
+	fmt.Fprintf(writer, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func formatUint(value uint64) string {
+	return strconv.FormatUint(value, 10)
+}
+
+// Escapes backslash, double quote and line feed as required in a Prometheus label value.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func quoteLabel(value string) string {
+	return `"` + labelEscaper.Replace(value) + `"`
+}
@@ -0,0 +1,96 @@
+// Copyright 2026 Jeffrey Bester <jbester@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and
+// to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of
+// the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package metrics
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"bitbucket.org/jbester/sync/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+// Verify the expvar form of the registered sources
+func Test_ExpvarSamples(t *testing.T) {
+	var registry = MakeRegistry()
+	registry.Register("pool", levelSource{MakeCollector(clock.Real()), 2, 4})
+	registry.Register("plain", MakeCollector(clock.Real()))
+
+	var encoded, err = json.Marshal(registry.expvarSamples())
+	assert.NoError(t, err)
+	var result map[string]map[string]interface{}
+	assert.NoError(t, json.Unmarshal(encoded, &result))
+	assert.Equal(t, float64(2), result["pool"]["count"])
+	assert.Equal(t, float64(4), result["pool"]["capacity"])
+	assert.Equal(t, float64(0), result["plain"]["acquisitions"])
+	var _, hasCount = result["plain"]["count"]
+	assert.False(t, hasCount)
+}
+
+// expvar names are process wide, so each run publishes under a name not yet taken
+var publishCount int32 = 0
+
+func unpublishedName(prefix string) string {
+	for {
+		var name = fmt.Sprintf("%s_%d", prefix, atomic.AddInt32(&publishCount, 1))
+		if expvar.Get(name) == nil {
+			return name
+		}
+	}
+}
+
+// Verify sources registered after publishing are exported through expvar
+func Test_Publish(t *testing.T) {
+	var registry = MakeRegistry()
+	var name = unpublishedName(t.Name())
+	registry.Publish(name)
+	registry.Register("pool", levelSource{MakeCollector(clock.Real()), 2, 4})
+
+	var result map[string]map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(expvar.Get(name).String()), &result))
+	assert.Equal(t, float64(4), result["pool"]["capacity"])
+}
+
+func Test_Handler(t *testing.T) {
+	var fake = clock.MakeFakeClock(time.Now())
+	var registry = MakeRegistry()
+	var collector = MakeCollector(fake)
+	var wait = collector.BeginWait()
+	fake.Advance(time.Millisecond * 5)
+	wait.End(TimedOut)
+	collector.Acquired()
+	registry.Register(`db "conns"`, levelSource{collector, 1, 20})
+
+	var recorder = httptest.NewRecorder()
+	registry.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	var body = recorder.Body.String()
+	assert.Contains(t, recorder.Header().Get("Content-Type"), "text/plain")
+	assert.Contains(t, body, "# TYPE sync_acquisitions_total counter\n")
+	assert.Contains(t, body, `sync_acquisitions_total{name="db \"conns\""} 1`+"\n")
+	assert.Contains(t, body, `sync_timeouts_total{name="db \"conns\""} 1`+"\n")
+	assert.Contains(t, body, `sync_count{name="db \"conns\""} 1`+"\n")
+	assert.Contains(t, body, `sync_capacity{name="db \"conns\""} 20`+"\n")
+	assert.Contains(t, body, `sync_wait_seconds_bucket{name="db \"conns\"",le="0.001"} 0`+"\n")
+	assert.Contains(t, body, `sync_wait_seconds_bucket{name="db \"conns\"",le="0.01"} 1`+"\n")
+	assert.Contains(t, body, `sync_wait_seconds_bucket{name="db \"conns\"",le="+Inf"} 1`+"\n")
+	assert.Contains(t, body, `sync_wait_seconds_sum{name="db \"conns\""} 0.005`+"\n")
+	assert.Contains(t, body, `sync_wait_seconds_count{name="db \"conns\""} 1`+"\n")
+}
@@ -0,0 +1,184 @@
+// Copyright 2026 Jeffrey Bester <jbester@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and
+// to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of
+// the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package metrics provides runtime statistics for the primitives in this module.  Primitives count
+// the operations that acquire them or are released by them, how many of those had to wait, and how
+// long the waits took.  A snapshot of the counters is returned by the Stats method of each
+// primitive.
+//
+// Primitives created by the named constructors, such as semaphores.MakeNamedCountingSemaphore, are
+// added to a Registry.  A registry publishes the statistics of its primitives through expvar and
+// serves them in the Prometheus text format from an http.Handler.
+package metrics
+
+import (
+	"sync/atomic"
+	"time"
+
+	"bitbucket.org/jbester/sync/clock"
+)
+
+// Upper bounds of the wait time histogram buckets.  Waits longer than the last bound are counted
+// in a final overflow bucket.  A collector copies the bounds when it is created, so changing them
+// affects only collectors created afterwards.
+var WaitBuckets = []time.Duration{
+	10 * time.Microsecond,
+	100 * time.Microsecond,
+	time.Millisecond,
+	10 * time.Millisecond,
+	100 * time.Millisecond,
+	time.Second,
+	10 * time.Second,
+}
+
+// Outcome of a wait on a primitive.
+type Outcome int
+
+const (
+	// The wait acquired the primitive or was released by it.
+	Acquired Outcome = iota
+
+	// The wait gave up when its timeout expired.
+	TimedOut
+
+	// The wait gave up when its context was cancelled.
+	Cancelled
+)
+
+// A Histogram counts wait times.  Counts[i] is the number of waits no longer than Bounds[i] and
+// longer than Bounds[i-1]; the final count is the number of waits longer than every bound.
+type Histogram struct {
+	Bounds []time.Duration
+	Counts []uint64
+}
+
+// Stats is a snapshot of the statistics of a primitive.  Counters are read individually so a
+// snapshot taken while the primitive is in use may not be exactly consistent.
+type Stats struct {
+	// Operations that acquired the primitive or were released by it, with or without waiting.
+	Acquisitions uint64
+
+	// Waits that gave up when their timeout expired.
+	Timeouts uint64
+
+	// Waits that gave up when their context was cancelled.
+	Cancellations uint64
+
+	// Routines currently waiting.
+	Waiters int64
+
+	// The largest number of routines observed waiting at once.
+	MaxWaiters int64
+
+	// Total time spent by routines that had to wait.
+	TotalWaitTime time.Duration
+
+	// Distribution of the time spent by routines that had to wait.
+	WaitTimes Histogram
+}
+
+// A Source provides statistics.
+type Source interface {
+	// Stats returns a snapshot of the statistics.
+	Stats() Stats
+}
+
+// A Collector accumulates the statistics of a primitive.  It is safe for concurrent use.
+type Collector struct {
+	clock         clock.Clock
+	acquisitions  uint64
+	timeouts      uint64
+	cancellations uint64
+	waiters       int64
+	maxWaiters    int64
+	totalWaitTime int64
+	bounds        []time.Duration
+	buckets       []uint64
+}
+
+// A Wait is an in progress wait started by Collector.BeginWait.
+type Wait struct {
+	collector *Collector
+	start     time.Time
+}
+
+// Create a collector timing waits with the clock.
+func MakeCollector(c clock.Clock) *Collector {
+	var bounds = append([]time.Duration(nil), WaitBuckets...)
+	return &Collector{
+		clock:   c,
+		bounds:  bounds,
+		buckets: make([]uint64, len(bounds)+1),
+	}
+}
+
+// Acquired records an operation that acquired the primitive without waiting.
+func (collector *Collector) Acquired() {
+	atomic.AddUint64(&collector.acquisitions, 1)
+}
+
+// BeginWait records a routine starting to wait.  The wait must be ended with Wait.End.
+func (collector *Collector) BeginWait() Wait {
+	var waiters = atomic.AddInt64(&collector.waiters, 1)
+	for {
+		var max = atomic.LoadInt64(&collector.maxWaiters)
+		if waiters <= max || atomic.CompareAndSwapInt64(&collector.maxWaiters, max, waiters) {
+			break
+		}
+	}
+	return Wait{collector: collector, start: collector.clock.Now()}
+}
+
+// End records the wait finishing with the outcome.
+func (wait Wait) End(outcome Outcome) {
+	var collector = wait.collector
+	var elapsed = collector.clock.Since(wait.start)
+	atomic.AddInt64(&collector.waiters, -1)
+	atomic.AddInt64(&collector.totalWaitTime, int64(elapsed))
+	var bucket = 0
+	for bucket < len(collector.bounds) && elapsed > collector.bounds[bucket] {
+		bucket++
+	}
+	atomic.AddUint64(&collector.buckets[bucket], 1)
+
+	switch outcome {
+	case Acquired:
+		atomic.AddUint64(&collector.acquisitions, 1)
+	case TimedOut:
+		atomic.AddUint64(&collector.timeouts, 1)
+	case Cancelled:
+		atomic.AddUint64(&collector.cancellations, 1)
+	}
+}
+
+// Stats returns a snapshot of the statistics.
+func (collector *Collector) Stats() Stats {
+	var counts = make([]uint64, len(collector.buckets))
+	for i := range collector.buckets {
+		counts[i] = atomic.LoadUint64(&collector.buckets[i])
+	}
+	return Stats{
+		Acquisitions:  atomic.LoadUint64(&collector.acquisitions),
+		Timeouts:      atomic.LoadUint64(&collector.timeouts),
+		Cancellations: atomic.LoadUint64(&collector.cancellations),
+		Waiters:       atomic.LoadInt64(&collector.waiters),
+		MaxWaiters:    atomic.LoadInt64(&collector.maxWaiters),
+		TotalWaitTime: time.Duration(atomic.LoadInt64(&collector.totalWaitTime)),
+		WaitTimes: Histogram{
+			Bounds: append([]time.Duration(nil), collector.bounds...),
+			Counts: counts,
+		},
+	}
+}
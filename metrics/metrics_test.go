@@ -0,0 +1,103 @@
+// Copyright 2026 Jeffrey Bester <jbester@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and
+// to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of
+// the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package metrics
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"bitbucket.org/jbester/sync/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Acquired(t *testing.T) {
+	var collector = MakeCollector(clock.Real())
+	collector.Acquired()
+	var stats = collector.Stats()
+	assert.Equal(t, uint64(1), stats.Acquisitions)
+	assert.Equal(t, int64(0), stats.Waiters)
+	assert.Equal(t, time.Duration(0), stats.TotalWaitTime)
+}
+
+func Test_WaitOutcomes(t *testing.T) {
+	var fake = clock.MakeFakeClock(time.Now())
+	var collector = MakeCollector(fake)
+	var first = collector.BeginWait()
+	var second = collector.BeginWait()
+	var third = collector.BeginWait()
+	assert.Equal(t, int64(3), collector.Stats().Waiters)
+
+	fake.Advance(time.Millisecond * 5)
+	first.End(Acquired)
+	fake.Advance(time.Second)
+	second.End(TimedOut)
+	third.End(Cancelled)
+
+	var stats = collector.Stats()
+	assert.Equal(t, uint64(1), stats.Acquisitions)
+	assert.Equal(t, uint64(1), stats.Timeouts)
+	assert.Equal(t, uint64(1), stats.Cancellations)
+	assert.Equal(t, int64(0), stats.Waiters)
+	assert.Equal(t, int64(3), stats.MaxWaiters)
+	assert.Equal(t, time.Millisecond*5+2*(time.Millisecond*1005), stats.TotalWaitTime)
+	// 5ms falls in the 10ms bucket and 1.005s in the 10s bucket
+	assert.Equal(t, []uint64{0, 0, 0, 1, 0, 0, 2, 0}, stats.WaitTimes.Counts)
+	assert.Equal(t, WaitBuckets, stats.WaitTimes.Bounds)
+}
+
+// Verify a collector keeps the bounds it was created with
+func Test_WaitBucketsChanged(t *testing.T) {
+	var fake = clock.MakeFakeClock(time.Now())
+	var collector = MakeCollector(fake)
+	var original = WaitBuckets
+	defer func() {
+		WaitBuckets = original
+	}()
+	WaitBuckets = append(append([]time.Duration(nil), original...), time.Minute, time.Hour)
+
+	var wait = collector.BeginWait()
+	fake.Advance(time.Hour)
+	wait.End(Acquired)
+	var stats = collector.Stats()
+	assert.Equal(t, original, stats.WaitTimes.Bounds)
+	assert.Equal(t, []uint64{0, 0, 0, 0, 0, 0, 0, 1}, stats.WaitTimes.Counts)
+	assert.Len(t, MakeCollector(fake).Stats().WaitTimes.Counts, len(original)+3)
+}
+
+func Test_MaxWaitersConcurrent(t *testing.T) {
+	var collector = MakeCollector(clock.Real())
+	var started = &sync.WaitGroup{}
+	var release = make(chan struct{})
+	var done = &sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		started.Add(1)
+		done.Add(1)
+		go func() {
+			defer done.Done()
+			var wait = collector.BeginWait()
+			started.Done()
+			<-release
+			wait.End(Acquired)
+		}()
+	}
+	started.Wait()
+	close(release)
+	done.Wait()
+	var stats = collector.Stats()
+	assert.Equal(t, int64(10), stats.MaxWaiters)
+	assert.Equal(t, uint64(10), stats.Acquisitions)
+	assert.Equal(t, int64(0), stats.Waiters)
+}
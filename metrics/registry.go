@@ -0,0 +1,121 @@
+// Copyright 2026 Jeffrey Bester <jbester@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and
+// to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of
+// the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package metrics
+
+import (
+	"sort"
+	"sync"
+)
+
+// A Level is a Source that also reports how full the primitive is, such as the count of a
+// semaphore and the largest count it can hold.
+type Level interface {
+	Source
+
+	// Level returns the current count and the capacity.
+	Level() (count int64, capacity int64)
+}
+
+// A Registry holds named sources so their statistics can be exported.  It is safe for
+// concurrent use.
+type Registry struct {
+	lock    sync.Mutex
+	sources map[string]Source
+}
+
+// The registry used by the named constructors of the primitives unless another is given.
+var DefaultRegistry = MakeRegistry()
+
+// Create an empty registry.
+func MakeRegistry() *Registry {
+	return &Registry{sources: make(map[string]Source)}
+}
+
+// Register adds a source under the name.  Panics if the name is already registered.
+func (registry *Registry) Register(name string, source Source) {
+	registry.lock.Lock()
+	defer registry.lock.Unlock()
+	if _, ok := registry.sources[name]; ok {
+		panic("metrics source already registered: " + name)
+	}
+	registry.sources[name] = source
+}
+
+// Unregister removes the source registered under the name.  Returns false if no source was
+// registered under the name.
+func (registry *Registry) Unregister(name string) bool {
+	registry.lock.Lock()
+	defer registry.lock.Unlock()
+	var _, ok = registry.sources[name]
+	delete(registry.sources, name)
+	return ok
+}
+
+// Lookup returns the source registered under the name.
+func (registry *Registry) Lookup(name string) (Source, bool) {
+	registry.lock.Lock()
+	defer registry.lock.Unlock()
+	var source, ok = registry.sources[name]
+	return source, ok
+}
+
+// Names returns the registered names in sorted order.
+func (registry *Registry) Names() []string {
+	registry.lock.Lock()
+	defer registry.lock.Unlock()
+	var names = make([]string, 0, len(registry.sources))
+	for name := range registry.sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// A Sample is the statistics of a registered source taken by Registry.Snapshot.
+type Sample struct {
+	Name string
+	Stats
+
+	// Whether the source reports a level.  Count and Capacity are zero otherwise.
+	HasLevel bool
+	Count    int64
+	Capacity int64
+}
+
+// Snapshot returns the statistics of every registered source sorted by name.
+func (registry *Registry) Snapshot() []Sample {
+	registry.lock.Lock()
+	var samples = make([]Sample, 0, len(registry.sources))
+	var sources = make([]Source, 0, len(registry.sources))
+	for name, source := range registry.sources {
+		samples = append(samples, Sample{Name: name})
+		sources = append(sources, source)
+	}
+	registry.lock.Unlock()
+
+	// query the sources outside the lock; a source may itself use the registry
+	for i, source := range sources {
+		samples[i].Stats = source.Stats()
+		if level, ok := source.(Level); ok {
+			samples[i].HasLevel = true
+			samples[i].Count, samples[i].Capacity = level.Level()
+		}
+	}
+	sort.Slice(samples, func(i, j int) bool {
+		return samples[i].Name < samples[j].Name
+	})
+	return samples
+}
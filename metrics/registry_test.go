@@ -0,0 +1,78 @@
+// Copyright 2026 Jeffrey Bester <jbester@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and
+// to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of
+// the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"bitbucket.org/jbester/sync/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+// A source reporting a fixed level.
+type levelSource struct {
+	*Collector
+	count    int64
+	capacity int64
+}
+
+func (source levelSource) Level() (int64, int64) {
+	return source.count, source.capacity
+}
+
+func Test_RegisterLookup(t *testing.T) {
+	var registry = MakeRegistry()
+	var collector = MakeCollector(clock.Real())
+	registry.Register("b", collector)
+	registry.Register("a", collector)
+	var source, ok = registry.Lookup("a")
+	assert.True(t, ok)
+	assert.Equal(t, Source(collector), source)
+	assert.Equal(t, []string{"a", "b"}, registry.Names())
+
+	assert.True(t, registry.Unregister("a"))
+	assert.False(t, registry.Unregister("a"))
+	_, ok = registry.Lookup("a")
+	assert.False(t, ok)
+}
+
+func Test_RegisterDuplicate(t *testing.T) {
+	var registry = MakeRegistry()
+	registry.Register("a", MakeCollector(clock.Real()))
+	assert.Panics(t, func() {
+		registry.Register("a", MakeCollector(clock.Real()))
+	})
+}
+
+func Test_Snapshot(t *testing.T) {
+	var fake = clock.MakeFakeClock(time.Now())
+	var registry = MakeRegistry()
+	var plain = MakeCollector(fake)
+	plain.Acquired()
+	registry.Register("plain", plain)
+	registry.Register("level", levelSource{MakeCollector(fake), 3, 5})
+
+	var samples = registry.Snapshot()
+	assert.Len(t, samples, 2)
+	assert.Equal(t, "level", samples[0].Name)
+	assert.True(t, samples[0].HasLevel)
+	assert.Equal(t, int64(3), samples[0].Count)
+	assert.Equal(t, int64(5), samples[0].Capacity)
+	assert.Equal(t, "plain", samples[1].Name)
+	assert.False(t, samples[1].HasLevel)
+	assert.Equal(t, uint64(1), samples[1].Acquisitions)
+}
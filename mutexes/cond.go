@@ -0,0 +1,184 @@
+// Copyright 2026 Jeffrey Bester <jbester@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and
+// to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of
+// the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package mutexes
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"bitbucket.org/jbester/sync/clock"
+	"bitbucket.org/jbester/sync/metrics"
+	"bitbucket.org/jbester/sync/semaphores"
+	"bitbucket.org/jbester/sync/watchdog"
+)
+
+// A Cond is a condition variable, a point at which routines wait for a condition protected by a
+// lock to change.  The lock is held when calling a wait, which releases the lock while waiting and
+// holds it again before returning.  As with sync.Cond a waiting routine should recheck its
+// condition in a loop after each wait returns.  Unlike sync.Cond waits can time out or be
+// cancelled.
+type Cond interface {
+	//  Release the lock and wait until signalled, then lock the lock again.
+	Wait()
+
+	//  Release the lock and wait up to the timeout until signalled, then lock the lock again.
+	//  Returns false if the wait timed out without being signalled.
+	WaitTimeout(timeout time.Duration) bool
+
+	//  Release the lock and wait until signalled or the context is done, then lock the lock
+	//  again.  Returns the context's error if the wait ended without being signalled.  Locking
+	//  the lock again is not cancelled by the context.
+	WaitContext(ctx context.Context) error
+
+	//  Wake the routine that has been waiting longest, if any.
+	Signal()
+
+	//  Wake every waiting routine.
+	Broadcast()
+
+	//  Locker returns the lock associated with the condition.
+	Locker() sync.Locker
+
+	//  Returns a snapshot of the condition's statistics.
+	Stats() metrics.Stats
+}
+
+type cond struct {
+	locker   sync.Locker
+	lock     *sync.Mutex
+	waiters  *list.List
+	clock    clock.Clock
+	stats    *metrics.Collector
+	watchdog watchdog.Watchdog
+}
+
+// Create a condition variable protected by the lock.  The lock may be a Mutex, the write lock of an
+// RWLock, the RLocker of an RWLock, the Locker of a RecursiveMutex or PriorityInheritanceMutex, a
+// binary semaphore adapted with SemaphoreLocker or any other sync.Locker.
+func MakeCond(locker sync.Locker, opts ...Option) Cond {
+	var options = makeOptions(opts)
+	return &cond{
+		locker:   locker,
+		lock:     &sync.Mutex{},
+		waiters:  list.New(),
+		clock:    options.clock,
+		stats:    metrics.MakeCollector(options.clock),
+		watchdog: options.watchdog,
+	}
+}
+
+// Wait until signalled, the timeout expires or the done channel is closed.  Returns false if the
+// wait ended without being signalled.
+func (c *cond) wait(timeout time.Duration, done <-chan struct{}) bool {
+	var ready = make(chan struct{})
+	c.lock.Lock()
+	var element = c.waiters.PushBack(ready)
+	c.lock.Unlock()
+	c.locker.Unlock()
+	defer c.locker.Lock()
+
+	var wait = c.stats.BeginWait()
+	defer c.watchdog.Begin("cond")()
+	var expired <-chan time.Time
+	if timeout != forever {
+		var timer = c.clock.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C()
+	}
+	var failure = metrics.TimedOut
+	select {
+	case <-ready:
+		wait.End(metrics.Acquired)
+		return true
+	case <-expired:
+	case <-done:
+		failure = metrics.Cancelled
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	select {
+	case <-ready:
+		// signalled before the waiter could be removed; the signal is not lost
+		wait.End(metrics.Acquired)
+		return true
+	default:
+	}
+	c.waiters.Remove(element)
+	wait.End(failure)
+	return false
+}
+
+func (c *cond) Wait() {
+	c.wait(forever, nil)
+}
+
+func (c *cond) WaitTimeout(timeout time.Duration) bool {
+	return c.wait(timeout, nil)
+}
+
+func (c *cond) WaitContext(ctx context.Context) error {
+	if !c.wait(forever, ctx.Done()) {
+		return ctx.Err()
+	}
+	return nil
+}
+
+func (c *cond) Signal() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if front := c.waiters.Front(); front != nil {
+		c.waiters.Remove(front)
+		close(front.Value.(chan struct{}))
+	}
+}
+
+func (c *cond) Broadcast() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for front := c.waiters.Front(); front != nil; front = c.waiters.Front() {
+		c.waiters.Remove(front)
+		close(front.Value.(chan struct{}))
+	}
+}
+
+func (c *cond) Locker() sync.Locker {
+	return c.locker
+}
+
+func (c *cond) Stats() metrics.Stats {
+	return c.stats.Stats()
+}
+
+type semaphoreLocker struct {
+	semaphore semaphores.Semaphore
+}
+
+// SemaphoreLocker adapts a binary semaphore to a sync.Locker: Lock takes the semaphore and Unlock
+// gives it.
+func SemaphoreLocker(semaphore semaphores.Semaphore) sync.Locker {
+	return semaphoreLocker{semaphore: semaphore}
+}
+
+func (locker semaphoreLocker) Lock() {
+	locker.semaphore.Take()
+}
+
+func (locker semaphoreLocker) Unlock() {
+	locker.semaphore.Give()
+}
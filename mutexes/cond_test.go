@@ -0,0 +1,224 @@
+// Copyright 2026 Jeffrey Bester <jbester@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and
+// to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of
+// the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package mutexes
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"bitbucket.org/jbester/sync/clock"
+	"bitbucket.org/jbester/sync/semaphores"
+	"github.com/stretchr/testify/assert"
+)
+
+// wait until the given number of routines wait on the condition
+func waitForCondWaiters(c Cond, count int) {
+	var inner = c.(*cond)
+	for {
+		inner.lock.Lock()
+		var waiting = inner.waiters.Len()
+		inner.lock.Unlock()
+		if waiting == count {
+			return
+		}
+		<-time.After(time.Millisecond)
+	}
+}
+
+// A bounded queue built as a monitor on a mutex and two conditions.
+type boundedQueue struct {
+	mutex    Mutex
+	notFull  Cond
+	notEmpty Cond
+	items    []int
+	capacity int
+}
+
+func makeBoundedQueue(capacity int) *boundedQueue {
+	var mutex = MakeMutex()
+	return &boundedQueue{
+		mutex:    mutex,
+		notFull:  MakeCond(mutex),
+		notEmpty: MakeCond(mutex),
+		capacity: capacity,
+	}
+}
+
+func (queue *boundedQueue) put(item int) {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	for len(queue.items) == queue.capacity {
+		queue.notFull.Wait()
+	}
+	queue.items = append(queue.items, item)
+	queue.notEmpty.Signal()
+}
+
+func (queue *boundedQueue) get(timeout time.Duration) (int, bool) {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	for len(queue.items) == 0 {
+		if !queue.notEmpty.WaitTimeout(timeout) {
+			return 0, false
+		}
+	}
+	var item = queue.items[0]
+	queue.items = queue.items[1:]
+	queue.notFull.Signal()
+	return item, true
+}
+
+func Test_CondMonitor(t *testing.T) {
+	var queue = makeBoundedQueue(2)
+	var done = &sync.WaitGroup{}
+	done.Add(2)
+	for p := 0; p < 2; p++ {
+		go func() {
+			defer done.Done()
+			for i := 0; i < 50; i++ {
+				queue.put(i)
+			}
+		}()
+	}
+	var sum = 0
+	for i := 0; i < 100; i++ {
+		var item, ok = queue.get(time.Second)
+		assert.True(t, ok)
+		sum += item
+	}
+	done.Wait()
+	assert.Equal(t, 2*49*50/2, sum)
+	var _, ok = queue.get(time.Millisecond)
+	assert.False(t, ok)
+}
+
+func Test_CondSignalWakesOne(t *testing.T) {
+	var mutex = MakeMutex()
+	var c = MakeCond(mutex)
+	var woken = make(chan int, 2)
+	for i := 0; i < 2; i++ {
+		var id = i
+		go func() {
+			mutex.Lock()
+			c.Wait()
+			mutex.Unlock()
+			woken <- id
+		}()
+		waitForCondWaiters(c, i+1)
+	}
+	c.Signal()
+	assert.Equal(t, 0, <-woken)
+	select {
+	case <-woken:
+		assert.Fail(t, "signal woke more than one routine")
+	case <-time.After(time.Millisecond * 10):
+	}
+	c.Signal()
+	assert.Equal(t, 1, <-woken)
+}
+
+func Test_CondBroadcast(t *testing.T) {
+	var mutex = MakeMutex()
+	var c = MakeCond(mutex)
+	var done = &sync.WaitGroup{}
+	done.Add(3)
+	for i := 0; i < 3; i++ {
+		go func() {
+			defer done.Done()
+			mutex.Lock()
+			c.Wait()
+			mutex.Unlock()
+		}()
+	}
+	waitForCondWaiters(c, 3)
+	c.Broadcast()
+	done.Wait()
+	assert.Equal(t, uint64(3), c.Stats().Acquisitions)
+}
+
+func Test_CondWaitTimeout(t *testing.T) {
+	var fake = clock.MakeFakeClock(time.Now())
+	var mutex = MakeMutex(WithOwnershipCheck())
+	var c = MakeCond(mutex, WithClock(fake))
+	var result = make(chan bool)
+	go func() {
+		mutex.Lock()
+		result <- c.WaitTimeout(time.Second)
+		// the lock is held again after a timeout
+		result <- mutex.IsHeld()
+		mutex.Unlock()
+	}()
+	fake.WaitForTimers(1)
+	assert.False(t, mutex.IsLocked())
+	fake.Advance(time.Second)
+	assert.False(t, <-result)
+	assert.True(t, <-result)
+	waitForCondWaiters(c, 0)
+	assert.Equal(t, uint64(1), c.Stats().Timeouts)
+}
+
+func Test_CondWaitContext(t *testing.T) {
+	var mutex = MakeMutex()
+	var c = MakeCond(mutex)
+	var ctx, cancel = context.WithCancel(context.Background())
+	var result = make(chan error)
+	go func() {
+		mutex.Lock()
+		var err = c.WaitContext(ctx)
+		mutex.Unlock()
+		result <- err
+	}()
+	waitForCondWaiters(c, 1)
+	cancel()
+	assert.Equal(t, context.Canceled, <-result)
+	waitForCondWaiters(c, 0)
+
+	go func() {
+		mutex.Lock()
+		var err = c.WaitContext(context.Background())
+		mutex.Unlock()
+		result <- err
+	}()
+	waitForCondWaiters(c, 1)
+	c.Signal()
+	assert.NoError(t, <-result)
+}
+
+func Test_CondBinarySemaphore(t *testing.T) {
+	var semaphore = semaphores.MakeBinarySemaphore(true)
+	var locker = SemaphoreLocker(semaphore)
+	var c = MakeCond(locker)
+	assert.Equal(t, locker, c.Locker())
+	var ready = false
+	var done = make(chan struct{})
+	go func() {
+		locker.Lock()
+		for !ready {
+			c.Wait()
+		}
+		locker.Unlock()
+		close(done)
+	}()
+	waitForCondWaiters(c, 1)
+	locker.Lock()
+	ready = true
+	c.Broadcast()
+	locker.Unlock()
+	<-done
+	assert.True(t, semaphore.IsFull())
+}
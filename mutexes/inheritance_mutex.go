@@ -0,0 +1,297 @@
+// Copyright 2026 Jeffrey Bester <jbester@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and
+// to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of
+// the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package mutexes
+
+import (
+	"container/list"
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"bitbucket.org/jbester/sync/clock"
+	"bitbucket.org/jbester/sync/metrics"
+	"bitbucket.org/jbester/sync/tasks"
+	"bitbucket.org/jbester/sync/watchdog"
+)
+
+// A PriorityInheritanceMutex is a mutual exclusion lock held by tasks rather than routines.  Tasks
+// waiting for the mutex are served highest priority first.  While any task waits, the task holding
+// the mutex inherits the priority of the highest waiting task, so a low priority holder is not
+// kept waiting behind tasks of intermediate priority while a high priority task waits on it.  The
+// inherited priority is visible to every priority-ordered primitive the holder waits on and is
+// withdrawn when the mutex is unlocked.
+//
+// Inheritance is transitive: a holder waiting on another priority-inheritance mutex passes the
+// priority it inherits on to the holder of that mutex.
+type PriorityInheritanceMutex interface {
+	//  Lock the mutex for the task, waiting until it is available.  Panics if the task already
+	//  holds the mutex.
+	Lock(task tasks.Task)
+
+	//  Lock the mutex for the task, waiting up to the timeout until it is available.  Returns
+	//  false if the mutex was not locked.
+	TryLock(task tasks.Task, timeout time.Duration) bool
+
+	//  Lock the mutex for the task, waiting until it is available or the context is done.
+	//  Returns the context's error if the mutex was not locked.
+	LockContext(ctx context.Context, task tasks.Task) error
+
+	//  Unlock the mutex held by the task.  Panics if the task does not hold the mutex.
+	Unlock(task tasks.Task)
+
+	//  IsLocked tests if the mutex is held by any task.
+	IsLocked() bool
+
+	//  Owner returns the task holding the mutex or nil if the mutex is unlocked.
+	Owner() tasks.Task
+
+	//  Locker returns a sync.Locker that locks and unlocks the mutex for the task.
+	Locker(task tasks.Task) sync.Locker
+
+	//  Returns a snapshot of the mutex's statistics.
+	Stats() metrics.Stats
+}
+
+type inheritanceWaiter struct {
+	task     tasks.Task
+	priority int
+	ready    chan struct{}
+	element  *list.Element
+	granted  bool
+}
+
+type inheritanceMutex struct {
+	lock     *sync.Mutex
+	owner    tasks.Task
+	donation tasks.Donation
+	waiters  *list.List
+	clock    clock.Clock
+	stats    *metrics.Collector
+	watchdog watchdog.Watchdog
+}
+
+// Create an unlocked priority-inheritance mutex.
+func MakePriorityInheritanceMutex(opts ...Option) PriorityInheritanceMutex {
+	var options = makeOptions(opts)
+	return &inheritanceMutex{
+		lock:     &sync.Mutex{},
+		waiters:  list.New(),
+		clock:    options.clock,
+		stats:    metrics.MakeCollector(options.clock),
+		watchdog: options.watchdog,
+	}
+}
+
+// Waits forever when given as a timeout.
+const forever = time.Duration(math.MaxInt64)
+
+// Returns the priority of the highest waiting task.  This is the priority donated to the owner.
+func (mtx *inheritanceMutex) highestWaiting() int {
+	mtx.lock.Lock()
+	defer mtx.lock.Unlock()
+	if front := mtx.waiters.Front(); front != nil {
+		return front.Value.(*inheritanceWaiter).priority
+	}
+	return math.MinInt
+}
+
+// Make the owner inherit the priority of the waiting tasks.  Called by the routine that locked the
+// mutex.  The mutex's lock must not be held; the task consults the mutex for the priority.
+func (mtx *inheritanceMutex) inherit(task tasks.Task) {
+	var donation = task.Inherit(mtx.highestWaiting)
+	mtx.lock.Lock()
+	mtx.donation = donation
+	mtx.lock.Unlock()
+	// a task may have started waiting before the donation was recorded
+	donation.Refresh()
+}
+
+// Re-evaluate the priority inherited by the owner after the waiting tasks changed.  The mutex's
+// lock must not be held.
+func (mtx *inheritanceMutex) refresh() {
+	mtx.lock.Lock()
+	var donation = mtx.donation
+	mtx.lock.Unlock()
+	if donation != nil {
+		donation.Refresh()
+	}
+}
+
+// Queue a waiter behind all waiters of the same or higher priority.  Must be called with the lock
+// held.
+func (mtx *inheritanceMutex) enqueue(waiter *inheritanceWaiter) {
+	for e := mtx.waiters.Back(); e != nil; e = e.Prev() {
+		if e.Value.(*inheritanceWaiter).priority >= waiter.priority {
+			waiter.element = mtx.waiters.InsertAfter(waiter, e)
+			return
+		}
+	}
+	waiter.element = mtx.waiters.PushFront(waiter)
+}
+
+// Requeue a waiter after its task's priority changed.
+func (mtx *inheritanceMutex) reprioritize(waiter *inheritanceWaiter) {
+	mtx.lock.Lock()
+	if waiter.element == nil {
+		mtx.lock.Unlock()
+		return
+	}
+	mtx.waiters.Remove(waiter.element)
+	waiter.priority = waiter.task.Priority()
+	mtx.enqueue(waiter)
+	mtx.lock.Unlock()
+	mtx.refresh()
+}
+
+// Remove a waiter that stopped waiting.  Returns true if the mutex was handed to the waiter before
+// it could be removed.
+func (mtx *inheritanceMutex) abandon(waiter *inheritanceWaiter) bool {
+	mtx.lock.Lock()
+	if waiter.granted {
+		mtx.lock.Unlock()
+		return true
+	}
+	mtx.waiters.Remove(waiter.element)
+	waiter.element = nil
+	mtx.lock.Unlock()
+	// the owner may no longer inherit the abandoned waiter's priority
+	mtx.refresh()
+	return false
+}
+
+// Lock the mutex for the task until the timeout expires or the done channel is closed.  Returns
+// true if the mutex was locked.
+func (mtx *inheritanceMutex) acquire(task tasks.Task, timeout time.Duration, done <-chan struct{}) bool {
+	var waiter = &inheritanceWaiter{task: task}
+	// watch before reading the priority so no change is missed; watching takes the task's lock so
+	// it is done before taking the mutex's
+	defer task.Watch(func() {
+		mtx.reprioritize(waiter)
+	})()
+
+	mtx.lock.Lock()
+	if mtx.owner == task {
+		mtx.lock.Unlock()
+		panic("mutex locked recursively by the task holding it")
+	}
+	// the mutex is handed directly to the next waiter so an unowned mutex has no waiters
+	if mtx.owner == nil {
+		mtx.owner = task
+		mtx.lock.Unlock()
+		mtx.stats.Acquired()
+		mtx.inherit(task)
+		return true
+	}
+	waiter.priority = task.Priority()
+	waiter.ready = make(chan struct{})
+	mtx.enqueue(waiter)
+	mtx.lock.Unlock()
+	mtx.refresh()
+
+	var wait = mtx.stats.BeginWait()
+	defer mtx.watchdog.Begin("mutex")()
+	var expired <-chan time.Time
+	if timeout != forever {
+		var timer = mtx.clock.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C()
+	}
+	var failure = metrics.TimedOut
+	select {
+	case <-waiter.ready:
+	case <-expired:
+	case <-done:
+		failure = metrics.Cancelled
+	}
+	if !mtx.abandon(waiter) {
+		wait.End(failure)
+		return false
+	}
+	wait.End(metrics.Acquired)
+	mtx.inherit(task)
+	return true
+}
+
+func (mtx *inheritanceMutex) Lock(task tasks.Task) {
+	mtx.acquire(task, forever, nil)
+}
+
+func (mtx *inheritanceMutex) TryLock(task tasks.Task, timeout time.Duration) bool {
+	return mtx.acquire(task, timeout, nil)
+}
+
+func (mtx *inheritanceMutex) LockContext(ctx context.Context, task tasks.Task) error {
+	if !mtx.acquire(task, forever, ctx.Done()) {
+		return ctx.Err()
+	}
+	return nil
+}
+
+func (mtx *inheritanceMutex) Unlock(task tasks.Task) {
+	mtx.lock.Lock()
+	if mtx.owner != task {
+		mtx.lock.Unlock()
+		panic("mutex unlocked by a task that does not hold it")
+	}
+	var donation = mtx.donation
+	mtx.donation = nil
+	mtx.owner = nil
+	// hand the mutex to the highest priority waiter
+	if front := mtx.waiters.Front(); front != nil {
+		var waiter = front.Value.(*inheritanceWaiter)
+		mtx.waiters.Remove(front)
+		waiter.element = nil
+		waiter.granted = true
+		mtx.owner = waiter.task
+		close(waiter.ready)
+	}
+	mtx.lock.Unlock()
+	if donation != nil {
+		donation.Cancel()
+	}
+}
+
+func (mtx *inheritanceMutex) IsLocked() bool {
+	return mtx.Owner() != nil
+}
+
+func (mtx *inheritanceMutex) Owner() tasks.Task {
+	mtx.lock.Lock()
+	defer mtx.lock.Unlock()
+	return mtx.owner
+}
+
+func (mtx *inheritanceMutex) Stats() metrics.Stats {
+	return mtx.stats.Stats()
+}
+
+type inheritanceLocker struct {
+	mtx  *inheritanceMutex
+	task tasks.Task
+}
+
+func (locker inheritanceLocker) Lock() {
+	locker.mtx.Lock(locker.task)
+}
+
+func (locker inheritanceLocker) Unlock() {
+	locker.mtx.Unlock(locker.task)
+}
+
+func (mtx *inheritanceMutex) Locker(task tasks.Task) sync.Locker {
+	return inheritanceLocker{mtx: mtx, task: task}
+}
@@ -0,0 +1,244 @@
+// Copyright 2026 Jeffrey Bester <jbester@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and
+// to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of
+// the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package mutexes
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"bitbucket.org/jbester/sync/clock"
+	"bitbucket.org/jbester/sync/semaphores"
+	"bitbucket.org/jbester/sync/tasks"
+	"github.com/stretchr/testify/assert"
+)
+
+// wait until the given number of tasks are queued on the mutex
+func waitForTasks(mtx PriorityInheritanceMutex, count int) {
+	var inner = mtx.(*inheritanceMutex)
+	for {
+		inner.lock.Lock()
+		var waiting = inner.waiters.Len()
+		inner.lock.Unlock()
+		if waiting == count {
+			return
+		}
+		<-time.After(time.Millisecond)
+	}
+}
+
+// wait until the task's priority is the expected priority
+func waitForPriority(task tasks.Task, priority int) {
+	for task.Priority() != priority {
+		<-time.After(time.Millisecond)
+	}
+}
+
+func Test_InheritanceLockUnlock(t *testing.T) {
+	var mtx = MakePriorityInheritanceMutex()
+	var task = tasks.MakeTask(1)
+	assert.False(t, mtx.IsLocked())
+	mtx.Lock(task)
+	assert.True(t, mtx.IsLocked())
+	assert.Equal(t, task, mtx.Owner())
+	mtx.Unlock(task)
+	assert.False(t, mtx.IsLocked())
+	assert.Nil(t, mtx.Owner())
+}
+
+func Test_InheritanceOwnership(t *testing.T) {
+	var mtx = MakePriorityInheritanceMutex()
+	var owner, other = tasks.MakeTask(1), tasks.MakeTask(1)
+	assert.Panics(t, func() {
+		mtx.Unlock(owner)
+	})
+	mtx.Lock(owner)
+	assert.Panics(t, func() {
+		mtx.Unlock(other)
+	})
+	assert.Panics(t, func() {
+		mtx.Lock(owner)
+	})
+	mtx.Unlock(owner)
+}
+
+func Test_InheritPriority(t *testing.T) {
+	var mtx = MakePriorityInheritanceMutex()
+	var low, high = tasks.MakeTask(1), tasks.MakeTask(10)
+	mtx.Lock(low)
+	var done = make(chan struct{})
+	go func() {
+		mtx.Lock(high)
+		mtx.Unlock(high)
+		close(done)
+	}()
+	waitForPriority(low, 10)
+	assert.Equal(t, 1, low.BasePriority())
+
+	// the waiter's priority changes are passed on to the holder
+	high.SetPriority(12)
+	waitForPriority(low, 12)
+
+	mtx.Unlock(low)
+	<-done
+	assert.Equal(t, 1, low.Priority())
+	assert.Equal(t, 12, high.Priority())
+}
+
+func Test_InheritanceServesHighestPriority(t *testing.T) {
+	var mtx = MakePriorityInheritanceMutex()
+	var owner = tasks.MakeTask(0)
+	mtx.Lock(owner)
+	var order = make(chan int, 3)
+	for i, priority := range []int{1, 5, 3} {
+		var task = tasks.MakeTask(priority)
+		go func() {
+			mtx.Lock(task)
+			order <- task.Priority()
+			mtx.Unlock(task)
+		}()
+		waitForTasks(mtx, i+1)
+	}
+	mtx.Unlock(owner)
+	for _, expected := range []int{5, 3, 1} {
+		assert.Equal(t, expected, <-order)
+	}
+}
+
+func Test_InheritanceTimeoutWithdrawsPriority(t *testing.T) {
+	var fake = clock.MakeFakeClock(time.Now())
+	var mtx = MakePriorityInheritanceMutex(WithClock(fake))
+	var low, high = tasks.MakeTask(1), tasks.MakeTask(10)
+	mtx.Lock(low)
+	var result = make(chan bool)
+	go func() {
+		result <- mtx.TryLock(high, time.Second)
+	}()
+	fake.WaitForTimers(1)
+	assert.Equal(t, 10, low.Priority())
+	fake.Advance(time.Second)
+	assert.False(t, <-result)
+	assert.Equal(t, 1, low.Priority())
+	mtx.Unlock(low)
+}
+
+func Test_InheritanceLockContext(t *testing.T) {
+	var mtx = MakePriorityInheritanceMutex()
+	var low, high = tasks.MakeTask(1), tasks.MakeTask(10)
+	mtx.Lock(low)
+	var ctx, cancel = context.WithCancel(context.Background())
+	var result = make(chan error)
+	go func() {
+		result <- mtx.LockContext(ctx, high)
+	}()
+	waitForPriority(low, 10)
+	cancel()
+	assert.Equal(t, context.Canceled, <-result)
+	assert.Equal(t, 1, low.Priority())
+	mtx.Unlock(low)
+}
+
+// A holder waiting on a second mutex passes the inherited priority to that mutex's holder.
+func Test_InheritTransitively(t *testing.T) {
+	var first, second = MakePriorityInheritanceMutex(), MakePriorityInheritanceMutex()
+	var bottom, middle, top = tasks.MakeTask(1), tasks.MakeTask(2), tasks.MakeTask(10)
+	second.Lock(bottom)
+	first.Lock(middle)
+	var done = make(chan struct{}, 2)
+	go func() {
+		second.Lock(middle)
+		second.Unlock(middle)
+		first.Unlock(middle)
+		done <- struct{}{}
+	}()
+	waitForPriority(bottom, 2)
+	go func() {
+		first.Lock(top)
+		first.Unlock(top)
+		done <- struct{}{}
+	}()
+	waitForPriority(middle, 10)
+	waitForPriority(bottom, 10)
+
+	second.Unlock(bottom)
+	<-done
+	<-done
+	assert.Equal(t, 1, bottom.Priority())
+	assert.Equal(t, 2, middle.Priority())
+}
+
+// A holder queued on a priority semaphore moves ahead of intermediate priority tasks when it
+// inherits a priority, avoiding priority inversion.
+func Test_InheritanceAvoidsInversion(t *testing.T) {
+	var mtx = MakePriorityInheritanceMutex()
+	var processor = semaphores.MakePriorityBinarySemaphore(false)
+	var low, medium, high = tasks.MakeTask(1), tasks.MakeTask(5), tasks.MakeTask(10)
+	mtx.Lock(low)
+
+	var order = make(chan string, 2)
+	go func() {
+		processor.TakeTask(medium)
+		order <- "medium"
+	}()
+	go func() {
+		processor.TakeTask(low)
+		order <- "low"
+		mtx.Unlock(low)
+	}()
+	for processor.Stats().Waiters != 2 {
+		<-time.After(time.Millisecond)
+	}
+
+	var done = make(chan struct{})
+	go func() {
+		mtx.Lock(high)
+		mtx.Unlock(high)
+		close(done)
+	}()
+	waitForPriority(low, 10)
+	processor.Give()
+	assert.Equal(t, "low", <-order)
+	<-done
+	processor.Give()
+	assert.Equal(t, "medium", <-order)
+}
+
+// Verify the task's locker protects a condition variable
+func Test_InheritanceLockerCond(t *testing.T) {
+	var mtx = MakePriorityInheritanceMutex()
+	var waiter, signaller = tasks.MakeTask(1), tasks.MakeTask(1)
+	var c = MakeCond(mtx.Locker(waiter))
+	var ready = false
+	var done = make(chan struct{})
+	go func() {
+		c.Locker().Lock()
+		for !ready {
+			c.Wait()
+		}
+		assert.Equal(t, waiter, mtx.Owner())
+		c.Locker().Unlock()
+		close(done)
+	}()
+	waitForCondWaiters(c, 1)
+	var locker = mtx.Locker(signaller)
+	locker.Lock()
+	assert.Equal(t, signaller, mtx.Owner())
+	ready = true
+	c.Signal()
+	locker.Unlock()
+	<-done
+	assert.False(t, mtx.IsLocked())
+}
@@ -0,0 +1,150 @@
+// Copyright 2026 Jeffrey Bester <jbester@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and
+// to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of
+// the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package mutexes provides mutual exclusion locks that support timeouts and contexts.  Unlocking a
+// lock that is not held panics.  For debugging, a Mutex or RWLock created WithOwnershipCheck also
+// tracks the routine holding it, so unlocking from a routine that does not hold the lock is
+// detected rather than silently releasing another routine's critical section.
+//
+// Routines waiting for a Mutex are served in arrival order.  A PriorityInheritanceMutex is held by
+// tasks from the tasks package; waiting tasks are served by priority and lend their priority to
+// the task holding the mutex to avoid priority inversion.
+//
+// A RecursiveMutex may be locked repeatedly by its owner, identified by an explicit token.
+//
+// An RWLock is held by any number of readers or a single writer.  Its policy chooses whether
+// waiting readers or writers are admitted first, or whether read and write phases alternate.
+//
+// A Cond is a condition variable whose waits can time out or be cancelled.  It is used with a
+// Mutex, an RWLock or its RLocker, the Locker of a RecursiveMutex or PriorityInheritanceMutex
+// bound to an owner, or a binary semaphore adapted with SemaphoreLocker.
+package mutexes
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"bitbucket.org/jbester/sync/internal/goroutine"
+	"bitbucket.org/jbester/sync/metrics"
+	"bitbucket.org/jbester/sync/semaphores"
+)
+
+// A Mutex is a mutual exclusion lock held by at most one routine at a time.  It satisfies
+// sync.Locker.
+type Mutex interface {
+	//  Lock the mutex, waiting until it is available.  With WithOwnershipCheck, panics if the
+	//  calling routine already holds the mutex.
+	Lock()
+
+	//  Lock the mutex, waiting up to the timeout until it is available.  Returns false if the
+	//  mutex was not locked.
+	TryLock(timeout time.Duration) bool
+
+	//  Lock the mutex, waiting until it is available or the context is done.  Returns the
+	//  context's error if the mutex was not locked.
+	LockContext(ctx context.Context) error
+
+	//  Unlock the mutex.  Panics if the mutex is not locked or, with WithOwnershipCheck, if the
+	//  calling routine does not hold the mutex.
+	Unlock()
+
+	//  IsLocked tests if the mutex is held by any routine.
+	IsLocked() bool
+
+	//  IsHeld tests if the mutex is held by the calling routine.  Panics unless the mutex was
+	//  created WithOwnershipCheck.
+	IsHeld() bool
+
+	//  Returns a snapshot of the mutex's statistics.
+	Stats() metrics.Stats
+}
+
+type mutex struct {
+	signal    semaphores.Semaphore
+	ownership bool
+	owner     uint64
+}
+
+// Create an unlocked mutex.
+func MakeMutex(opts ...Option) Mutex {
+	var options = makeOptions(opts)
+	return &mutex{
+		signal: semaphores.MakeFairBinarySemaphore(true,
+			semaphores.WithClock(options.clock), semaphores.WithWatchdog(options.watchdog)),
+		ownership: options.ownership,
+	}
+}
+
+// Returns the id of the calling routine, panicking if it already holds the mutex.  Returns zero
+// without identifying the routine unless ownership is checked.
+func (mtx *mutex) caller() uint64 {
+	if !mtx.ownership {
+		return 0
+	}
+	var id = goroutine.Id()
+	if atomic.LoadUint64(&mtx.owner) == id {
+		panic("mutex locked recursively by the routine holding it")
+	}
+	return id
+}
+
+func (mtx *mutex) Lock() {
+	var id = mtx.caller()
+	mtx.signal.Take()
+	atomic.StoreUint64(&mtx.owner, id)
+}
+
+func (mtx *mutex) TryLock(timeout time.Duration) bool {
+	var id = mtx.caller()
+	if !mtx.signal.TryTake(timeout) {
+		return false
+	}
+	atomic.StoreUint64(&mtx.owner, id)
+	return true
+}
+
+func (mtx *mutex) LockContext(ctx context.Context) error {
+	var id = mtx.caller()
+	if err := mtx.signal.TakeContext(ctx); err != nil {
+		return err
+	}
+	atomic.StoreUint64(&mtx.owner, id)
+	return nil
+}
+
+func (mtx *mutex) Unlock() {
+	if mtx.ownership && !atomic.CompareAndSwapUint64(&mtx.owner, goroutine.Id(), 0) {
+		panic("mutex unlocked by a routine that does not hold it")
+	}
+	if !mtx.signal.Give() {
+		panic("mutex unlocked when not locked")
+	}
+}
+
+func (mtx *mutex) IsLocked() bool {
+	return !mtx.signal.IsFull()
+}
+
+func (mtx *mutex) IsHeld() bool {
+	if !mtx.ownership {
+		panic("mutex created without WithOwnershipCheck does not track the routine holding it")
+	}
+	return atomic.LoadUint64(&mtx.owner) == goroutine.Id()
+}
+
+func (mtx *mutex) Stats() metrics.Stats {
+	return mtx.signal.Stats()
+}
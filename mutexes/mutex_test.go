@@ -0,0 +1,169 @@
+// Copyright 2026 Jeffrey Bester <jbester@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and
+// to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of
+// the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package mutexes
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"bitbucket.org/jbester/sync/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ sync.Locker = MakeMutex()
+
+func Test_LockUnlock(t *testing.T) {
+	var mtx = MakeMutex(WithOwnershipCheck())
+	assert.False(t, mtx.IsLocked())
+	mtx.Lock()
+	assert.True(t, mtx.IsLocked())
+	assert.True(t, mtx.IsHeld())
+	mtx.Unlock()
+	assert.False(t, mtx.IsLocked())
+	assert.False(t, mtx.IsHeld())
+}
+
+func Test_MutualExclusion(t *testing.T) {
+	var mtx = MakeMutex()
+	var counter = 0
+	var done = &sync.WaitGroup{}
+	done.Add(4)
+	for i := 0; i < 4; i++ {
+		go func() {
+			defer done.Done()
+			for j := 0; j < 100; j++ {
+				mtx.Lock()
+				counter++
+				mtx.Unlock()
+			}
+		}()
+	}
+	done.Wait()
+	assert.Equal(t, 400, counter)
+}
+
+func Test_TryLock(t *testing.T) {
+	var mtx = MakeMutex()
+	assert.True(t, mtx.TryLock(time.Millisecond))
+	var result = make(chan bool)
+	go func() {
+		result <- mtx.TryLock(time.Millisecond)
+	}()
+	assert.False(t, <-result)
+	mtx.Unlock()
+}
+
+func Test_TryLockFakeClock(t *testing.T) {
+	var fake = clock.MakeFakeClock(time.Now())
+	var mtx = MakeMutex(WithClock(fake))
+	mtx.Lock()
+	var result = make(chan bool)
+	go func() {
+		result <- mtx.TryLock(time.Hour)
+	}()
+	fake.WaitForTimers(1)
+	fake.Advance(time.Hour)
+	assert.False(t, <-result)
+	mtx.Unlock()
+}
+
+func Test_LockContext(t *testing.T) {
+	var mtx = MakeMutex()
+	mtx.Lock()
+	var ctx, cancel = context.WithCancel(context.Background())
+	var result = make(chan error)
+	go func() {
+		result <- mtx.LockContext(ctx)
+	}()
+	cancel()
+	assert.Equal(t, context.Canceled, <-result)
+	mtx.Unlock()
+
+	go func() {
+		var err = mtx.LockContext(context.Background())
+		if err == nil {
+			mtx.Unlock()
+		}
+		result <- err
+	}()
+	assert.NoError(t, <-result)
+}
+
+func Test_UnlockNotLocked(t *testing.T) {
+	var mtx = MakeMutex()
+	assert.Panics(t, func() {
+		mtx.Unlock()
+	})
+	mtx.Lock()
+	mtx.Unlock()
+	assert.Panics(t, func() {
+		mtx.Unlock()
+	})
+	assert.False(t, mtx.IsLocked())
+}
+
+func Test_IsHeldWithoutOwnershipCheck(t *testing.T) {
+	var mtx = MakeMutex()
+	assert.Panics(t, func() {
+		mtx.IsHeld()
+	})
+}
+
+func Test_UnlockByNonOwner(t *testing.T) {
+	var mtx = MakeMutex(WithOwnershipCheck())
+	assert.Panics(t, func() {
+		mtx.Unlock()
+	})
+	var locked = make(chan struct{})
+	var release = make(chan struct{})
+	go func() {
+		mtx.Lock()
+		close(locked)
+		<-release
+		mtx.Unlock()
+	}()
+	<-locked
+	assert.Panics(t, func() {
+		mtx.Unlock()
+	})
+	// the owner's critical section is undisturbed
+	assert.True(t, mtx.IsLocked())
+	close(release)
+	mtx.Lock()
+	mtx.Unlock()
+}
+
+func Test_LockRecursive(t *testing.T) {
+	var mtx = MakeMutex(WithOwnershipCheck())
+	mtx.Lock()
+	assert.Panics(t, func() {
+		mtx.Lock()
+	})
+	assert.Panics(t, func() {
+		mtx.TryLock(time.Millisecond)
+	})
+	mtx.Unlock()
+}
+
+func Benchmark_LockUnlock(b *testing.B) {
+	var mtx = MakeMutex()
+	for n := 0; n < b.N; n++ {
+		mtx.Lock()
+		mtx.Unlock()
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright 2026 Jeffrey Bester <jbester@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and
+// to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of
+// the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package mutexes
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"bitbucket.org/jbester/sync/metrics"
+	"bitbucket.org/jbester/sync/semaphores"
+)
+
+// A RecursiveMutex is a mutual exclusion lock that its owner may lock repeatedly.  The mutex is
+// released once the owner has unlocked it as many times as it locked it.  Owners are identified by
+// an explicit token rather than the calling routine, so a logical owner such as a task may lock
+// and unlock the mutex from different routines.  Any comparable value other than nil may be used
+// as an owner, for example a tasks.Task or a pointer allocated for each thread of control.
+type RecursiveMutex interface {
+	//  Lock the mutex for the owner, waiting until it is available unless the owner already
+	//  holds it.
+	Lock(owner interface{})
+
+	//  Lock the mutex for the owner, waiting up to the timeout until it is available unless the
+	//  owner already holds it.  Returns false if the mutex was not locked.
+	TryLock(owner interface{}, timeout time.Duration) bool
+
+	//  Lock the mutex for the owner, waiting until it is available or the context is done unless
+	//  the owner already holds it.  Returns the context's error if the mutex was not locked.
+	LockContext(ctx context.Context, owner interface{}) error
+
+	//  Release one hold of the mutex by the owner.  Panics if the owner does not hold the mutex.
+	Unlock(owner interface{})
+
+	//  HoldCount returns the number of times the owner holding the mutex has locked it, or zero
+	//  if the mutex is unlocked.
+	HoldCount() int
+
+	//  IsHeldBy tests if the mutex is held by the owner.
+	IsHeldBy(owner interface{}) bool
+
+	//  IsLocked tests if the mutex is held by any owner.
+	IsLocked() bool
+
+	//  Locker returns a sync.Locker that locks and unlocks the mutex for the owner.  A Cond
+	//  waiting with the locker releases a single hold, so the owner should hold the mutex once.
+	Locker(owner interface{}) sync.Locker
+
+	//  Returns a snapshot of the mutex's statistics.  Only locks that were not already held by
+	//  the owner are counted.
+	Stats() metrics.Stats
+}
+
+type recursiveMutex struct {
+	lock   *sync.Mutex
+	signal semaphores.Semaphore
+	owner  interface{}
+	holds  int
+}
+
+// Create an unlocked recursive mutex.
+func MakeRecursiveMutex(opts ...Option) RecursiveMutex {
+	var options = makeOptions(opts)
+	return &recursiveMutex{
+		lock: &sync.Mutex{},
+		signal: semaphores.MakeFairBinarySemaphore(true,
+			semaphores.WithClock(options.clock), semaphores.WithWatchdog(options.watchdog)),
+	}
+}
+
+// Add a hold if the owner already holds the mutex.  Returns false if the owner must wait.
+func (mtx *recursiveMutex) reenter(owner interface{}) bool {
+	if owner == nil {
+		panic("recursive mutex locked by a nil owner")
+	}
+	mtx.lock.Lock()
+	defer mtx.lock.Unlock()
+	if mtx.holds > 0 && mtx.owner == owner {
+		mtx.holds++
+		return true
+	}
+	return false
+}
+
+// Record the owner after the mutex was taken.
+func (mtx *recursiveMutex) acquired(owner interface{}) {
+	mtx.lock.Lock()
+	mtx.owner = owner
+	mtx.holds = 1
+	mtx.lock.Unlock()
+}
+
+func (mtx *recursiveMutex) Lock(owner interface{}) {
+	if mtx.reenter(owner) {
+		return
+	}
+	mtx.signal.Take()
+	mtx.acquired(owner)
+}
+
+func (mtx *recursiveMutex) TryLock(owner interface{}, timeout time.Duration) bool {
+	if mtx.reenter(owner) {
+		return true
+	}
+	if !mtx.signal.TryTake(timeout) {
+		return false
+	}
+	mtx.acquired(owner)
+	return true
+}
+
+func (mtx *recursiveMutex) LockContext(ctx context.Context, owner interface{}) error {
+	if mtx.reenter(owner) {
+		return nil
+	}
+	if err := mtx.signal.TakeContext(ctx); err != nil {
+		return err
+	}
+	mtx.acquired(owner)
+	return nil
+}
+
+func (mtx *recursiveMutex) Unlock(owner interface{}) {
+	mtx.lock.Lock()
+	if mtx.holds == 0 || mtx.owner != owner {
+		mtx.lock.Unlock()
+		panic("recursive mutex unlocked by an owner that does not hold it")
+	}
+	mtx.holds--
+	var released = mtx.holds == 0
+	if released {
+		mtx.owner = nil
+	}
+	mtx.lock.Unlock()
+	if released {
+		mtx.signal.Give()
+	}
+}
+
+func (mtx *recursiveMutex) HoldCount() int {
+	mtx.lock.Lock()
+	defer mtx.lock.Unlock()
+	return mtx.holds
+}
+
+func (mtx *recursiveMutex) IsHeldBy(owner interface{}) bool {
+	mtx.lock.Lock()
+	defer mtx.lock.Unlock()
+	return mtx.holds > 0 && mtx.owner == owner
+}
+
+func (mtx *recursiveMutex) IsLocked() bool {
+	return mtx.HoldCount() > 0
+}
+
+func (mtx *recursiveMutex) Stats() metrics.Stats {
+	return mtx.signal.Stats()
+}
+
+type recursiveLocker struct {
+	mtx   *recursiveMutex
+	owner interface{}
+}
+
+func (locker recursiveLocker) Lock() {
+	locker.mtx.Lock(locker.owner)
+}
+
+func (locker recursiveLocker) Unlock() {
+	locker.mtx.Unlock(locker.owner)
+}
+
+func (mtx *recursiveMutex) Locker(owner interface{}) sync.Locker {
+	return recursiveLocker{mtx: mtx, owner: owner}
+}
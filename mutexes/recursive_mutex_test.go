@@ -0,0 +1,144 @@
+// Copyright 2026 Jeffrey Bester <jbester@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and
+// to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of
+// the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package mutexes
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"bitbucket.org/jbester/sync/tasks"
+	"github.com/stretchr/testify/assert"
+)
+
+type owner struct {
+	name string
+}
+
+func Test_RecursiveLock(t *testing.T) {
+	var mtx = MakeRecursiveMutex()
+	var first = &owner{"first"}
+	assert.False(t, mtx.IsLocked())
+	mtx.Lock(first)
+	mtx.Lock(first)
+	assert.True(t, mtx.TryLock(first, 0))
+	assert.NoError(t, mtx.LockContext(context.Background(), first))
+	assert.Equal(t, 4, mtx.HoldCount())
+	assert.True(t, mtx.IsHeldBy(first))
+
+	for holds := 3; holds >= 0; holds-- {
+		mtx.Unlock(first)
+		assert.Equal(t, holds, mtx.HoldCount())
+	}
+	assert.False(t, mtx.IsLocked())
+	assert.False(t, mtx.IsHeldBy(first))
+}
+
+func Test_RecursiveExcludesOtherOwners(t *testing.T) {
+	var mtx = MakeRecursiveMutex()
+	var first, second = &owner{"first"}, &owner{"second"}
+	mtx.Lock(first)
+	mtx.Lock(first)
+	assert.False(t, mtx.TryLock(second, time.Millisecond))
+	assert.False(t, mtx.IsHeldBy(second))
+
+	var done = make(chan struct{})
+	go func() {
+		mtx.Lock(second)
+		close(done)
+	}()
+	mtx.Unlock(first)
+	select {
+	case <-done:
+		assert.Fail(t, "mutex released while still held")
+	case <-time.After(time.Millisecond * 10):
+	}
+	mtx.Unlock(first)
+	<-done
+	assert.True(t, mtx.IsHeldBy(second))
+	assert.Equal(t, 1, mtx.HoldCount())
+	mtx.Unlock(second)
+}
+
+// An owner may lock and unlock the mutex from different routines.
+func Test_RecursiveOwnerAcrossRoutines(t *testing.T) {
+	var mtx = MakeRecursiveMutex()
+	var task = tasks.MakeTask(1)
+	mtx.Lock(task)
+	var done = make(chan struct{})
+	go func() {
+		mtx.Lock(task)
+		mtx.Unlock(task)
+		mtx.Unlock(task)
+		close(done)
+	}()
+	<-done
+	assert.False(t, mtx.IsLocked())
+}
+
+func Test_RecursiveUnlockByNonOwner(t *testing.T) {
+	var mtx = MakeRecursiveMutex()
+	var first, second = &owner{"first"}, &owner{"second"}
+	assert.Panics(t, func() {
+		mtx.Unlock(first)
+	})
+	mtx.Lock(first)
+	assert.Panics(t, func() {
+		mtx.Unlock(second)
+	})
+	assert.Panics(t, func() {
+		mtx.Lock(nil)
+	})
+	assert.Equal(t, 1, mtx.HoldCount())
+	mtx.Unlock(first)
+}
+
+func Test_RecursiveLockContext(t *testing.T) {
+	var mtx = MakeRecursiveMutex()
+	var first, second = &owner{"first"}, &owner{"second"}
+	mtx.Lock(first)
+	var ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, context.Canceled, mtx.LockContext(ctx, second))
+	// the holding owner re-enters even with a cancelled context
+	assert.NoError(t, mtx.LockContext(ctx, first))
+	mtx.Unlock(first)
+	mtx.Unlock(first)
+}
+
+// Verify the owner's locker protects a condition variable
+func Test_RecursiveLockerCond(t *testing.T) {
+	var mtx = MakeRecursiveMutex()
+	var first, second = mtx.Locker(&owner{"first"}), mtx.Locker(&owner{"second"})
+	var c = MakeCond(first)
+	var ready = false
+	var done = make(chan struct{})
+	go func() {
+		first.Lock()
+		for !ready {
+			c.Wait()
+		}
+		first.Unlock()
+		close(done)
+	}()
+	waitForCondWaiters(c, 1)
+	second.Lock()
+	ready = true
+	c.Signal()
+	second.Unlock()
+	<-done
+	assert.False(t, mtx.IsLocked())
+}
@@ -0,0 +1,364 @@
+// Copyright 2026 Jeffrey Bester <jbester@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and
+// to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of
+// the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package mutexes
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"bitbucket.org/jbester/sync/clock"
+	"bitbucket.org/jbester/sync/internal/goroutine"
+	"bitbucket.org/jbester/sync/metrics"
+	"bitbucket.org/jbester/sync/watchdog"
+)
+
+// RWPolicy selects whether waiting readers or writers are admitted first.
+type RWPolicy int
+
+const (
+	// Readers are admitted whenever no writer holds the lock, even while writers wait.  Writers
+	// may starve under a steady stream of readers.
+	ReaderPreferred RWPolicy = iota
+
+	// Readers are not admitted while a writer waits and a releasing writer hands the lock to the
+	// next writer.  Readers may starve under a steady stream of writers.
+	WriterPreferred
+
+	// Read and write phases alternate.  Readers are not admitted while a writer waits, and a
+	// releasing writer admits every reader that arrived during its phase before the next writer.
+	// Neither readers nor writers starve.
+	PhaseFair
+)
+
+func (policy RWPolicy) String() string {
+	switch policy {
+	case ReaderPreferred:
+		return "reader preferred"
+	case WriterPreferred:
+		return "writer preferred"
+	case PhaseFair:
+		return "phase fair"
+	}
+	return "unknown"
+}
+
+// An RWLock is a reader/writer mutual exclusion lock.  The lock is held by any number of readers
+// or by a single writer.  Created WithOwnershipCheck, the routine holding the write lock is tracked,
+// so unlocking from any other routine is detected.  Routines waiting for the same kind of lock are
+// served in arrival order.
+type RWLock interface {
+	//  Lock for reading, waiting until no writer holds the lock and the policy admits readers.
+	RLock()
+
+	//  Lock for reading, waiting up to the timeout.  Returns false if the lock was not taken.
+	RLockTimeout(timeout time.Duration) bool
+
+	//  Lock for reading, waiting until the lock is taken or the context is done.  Returns the
+	//  context's error if the lock was not taken.
+	RLockContext(ctx context.Context) error
+
+	//  Release a read lock.  Panics if the lock is not held for reading.
+	RUnlock()
+
+	//  Lock for writing, waiting until no routine holds the lock.  With WithOwnershipCheck,
+	//  panics if the calling routine already holds the write lock.
+	Lock()
+
+	//  Lock for writing, waiting up to the timeout.  Returns false if the lock was not taken.
+	LockTimeout(timeout time.Duration) bool
+
+	//  Lock for writing, waiting until the lock is taken or the context is done.  Returns the
+	//  context's error if the lock was not taken.
+	LockContext(ctx context.Context) error
+
+	//  Release the write lock.  Panics if the lock is not held for writing or, with
+	//  WithOwnershipCheck, if the calling routine does not hold the write lock.
+	Unlock()
+
+	//  RLocker returns a sync.Locker that read locks and unlocks the lock.
+	RLocker() sync.Locker
+
+	//  Readers returns the number of routines holding the lock for reading.
+	Readers() int
+
+	//  IsLocked tests if a writer holds the lock.
+	IsLocked() bool
+
+	//  Policy returns the admission policy of the lock.
+	Policy() RWPolicy
+
+	//  Returns a snapshot of the lock's statistics.
+	Stats() metrics.Stats
+}
+
+type rwWaiter struct {
+	write   bool
+	owner   uint64
+	ready   chan struct{}
+	element *list.Element
+	granted bool
+}
+
+type rwLock struct {
+	lock           *sync.Mutex
+	policy         RWPolicy
+	readers        int
+	writer         bool
+	ownership      bool
+	owner          uint64
+	waitingReaders *list.List
+	waitingWriters *list.List
+	clock          clock.Clock
+	stats          *metrics.Collector
+	watchdog       watchdog.Watchdog
+}
+
+// Create an unlocked reader/writer lock admitting waiting routines according to the policy.
+func MakeRWLock(policy RWPolicy, opts ...Option) RWLock {
+	var options = makeOptions(opts)
+	return &rwLock{
+		lock:           &sync.Mutex{},
+		policy:         policy,
+		ownership:      options.ownership,
+		waitingReaders: list.New(),
+		waitingWriters: list.New(),
+		clock:          options.clock,
+		stats:          metrics.MakeCollector(options.clock),
+		watchdog:       options.watchdog,
+	}
+}
+
+// Tests if a reader may take the lock.  Must be called with the lock held.
+func (rw *rwLock) canRead() bool {
+	return !rw.writer && (rw.policy == ReaderPreferred || rw.waitingWriters.Len() == 0)
+}
+
+// Tests if a writer may take the lock.  Must be called with the lock held.
+func (rw *rwLock) canWrite() bool {
+	return !rw.writer && rw.readers == 0
+}
+
+// Grant the lock to the next waiting writer.  Returns false if no writer is waiting.  Must be
+// called with the lock held.
+func (rw *rwLock) grantWriter() bool {
+	var front = rw.waitingWriters.Front()
+	if front == nil {
+		return false
+	}
+	var waiter = front.Value.(*rwWaiter)
+	rw.waitingWriters.Remove(front)
+	rw.writer = true
+	rw.owner = waiter.owner
+	rw.grant(waiter)
+	return true
+}
+
+// Grant the lock to every waiting reader.  Must be called with the lock held.
+func (rw *rwLock) grantReaders() {
+	for front := rw.waitingReaders.Front(); front != nil; front = rw.waitingReaders.Front() {
+		rw.waitingReaders.Remove(front)
+		rw.readers++
+		rw.grant(front.Value.(*rwWaiter))
+	}
+}
+
+func (rw *rwLock) grant(waiter *rwWaiter) {
+	waiter.element = nil
+	waiter.granted = true
+	close(waiter.ready)
+}
+
+// Hand the lock to waiting routines the policy admits.  writeEnded is true when a writer has just
+// released the lock.  Must be called with the lock held.
+func (rw *rwLock) dispatch(writeEnded bool) {
+	if rw.writer {
+		return
+	}
+	// a releasing writer starts a read phase unless writers are preferred
+	var readPhase = writeEnded && rw.policy != WriterPreferred
+	if !readPhase && rw.readers == 0 && rw.grantWriter() {
+		return
+	}
+	if readPhase || rw.canRead() {
+		rw.grantReaders()
+	}
+	if rw.readers == 0 {
+		rw.grantWriter()
+	}
+}
+
+// Returns the id of the calling routine, or zero without identifying the routine unless ownership
+// is checked.
+func (rw *rwLock) caller() uint64 {
+	if !rw.ownership {
+		return 0
+	}
+	return goroutine.Id()
+}
+
+// Take the lock for reading or writing until the timeout expires or the done channel is closed.
+// Returns true if the lock was taken.
+func (rw *rwLock) acquire(write bool, timeout time.Duration, done <-chan struct{}) bool {
+	var waiter = &rwWaiter{write: write}
+	var queue = rw.waitingReaders
+	if write {
+		waiter.owner = rw.caller()
+		queue = rw.waitingWriters
+	}
+
+	rw.lock.Lock()
+	if write && rw.ownership && rw.writer && rw.owner == waiter.owner {
+		rw.lock.Unlock()
+		panic("rwlock locked recursively by the routine holding it")
+	}
+	if write && rw.canWrite() {
+		rw.writer = true
+		rw.owner = waiter.owner
+		rw.lock.Unlock()
+		rw.stats.Acquired()
+		return true
+	}
+	if !write && rw.canRead() {
+		rw.readers++
+		rw.lock.Unlock()
+		rw.stats.Acquired()
+		return true
+	}
+	waiter.ready = make(chan struct{})
+	waiter.element = queue.PushBack(waiter)
+	rw.lock.Unlock()
+
+	var wait = rw.stats.BeginWait()
+	defer rw.watchdog.Begin("rwlock")()
+	var expired <-chan time.Time
+	if timeout != forever {
+		var timer = rw.clock.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C()
+	}
+	var failure = metrics.TimedOut
+	select {
+	case <-waiter.ready:
+		wait.End(metrics.Acquired)
+		return true
+	case <-expired:
+	case <-done:
+		failure = metrics.Cancelled
+	}
+
+	rw.lock.Lock()
+	defer rw.lock.Unlock()
+	if waiter.granted {
+		wait.End(metrics.Acquired)
+		return true
+	}
+	queue.Remove(waiter.element)
+	waiter.element = nil
+	// readers held back by an abandoned writer may now be admitted
+	rw.dispatch(false)
+	wait.End(failure)
+	return false
+}
+
+func (rw *rwLock) RLock() {
+	rw.acquire(false, forever, nil)
+}
+
+func (rw *rwLock) RLockTimeout(timeout time.Duration) bool {
+	return rw.acquire(false, timeout, nil)
+}
+
+func (rw *rwLock) RLockContext(ctx context.Context) error {
+	if !rw.acquire(false, forever, ctx.Done()) {
+		return ctx.Err()
+	}
+	return nil
+}
+
+func (rw *rwLock) RUnlock() {
+	rw.lock.Lock()
+	defer rw.lock.Unlock()
+	if rw.readers == 0 {
+		panic("rwlock read unlocked when not held for reading")
+	}
+	rw.readers--
+	rw.dispatch(false)
+}
+
+func (rw *rwLock) Lock() {
+	rw.acquire(true, forever, nil)
+}
+
+func (rw *rwLock) LockTimeout(timeout time.Duration) bool {
+	return rw.acquire(true, timeout, nil)
+}
+
+func (rw *rwLock) LockContext(ctx context.Context) error {
+	if !rw.acquire(true, forever, ctx.Done()) {
+		return ctx.Err()
+	}
+	return nil
+}
+
+func (rw *rwLock) Unlock() {
+	var id = rw.caller()
+	rw.lock.Lock()
+	defer rw.lock.Unlock()
+	if !rw.writer || rw.owner != id {
+		panic("rwlock unlocked by a routine that does not hold it for writing")
+	}
+	rw.writer = false
+	rw.owner = 0
+	rw.dispatch(true)
+}
+
+type readLocker struct {
+	rw *rwLock
+}
+
+func (locker readLocker) Lock() {
+	locker.rw.RLock()
+}
+
+func (locker readLocker) Unlock() {
+	locker.rw.RUnlock()
+}
+
+func (rw *rwLock) RLocker() sync.Locker {
+	return readLocker{rw: rw}
+}
+
+func (rw *rwLock) Readers() int {
+	rw.lock.Lock()
+	defer rw.lock.Unlock()
+	return rw.readers
+}
+
+func (rw *rwLock) IsLocked() bool {
+	rw.lock.Lock()
+	defer rw.lock.Unlock()
+	return rw.writer
+}
+
+func (rw *rwLock) Policy() RWPolicy {
+	return rw.policy
+}
+
+func (rw *rwLock) Stats() metrics.Stats {
+	return rw.stats.Stats()
+}
@@ -0,0 +1,241 @@
+// Copyright 2026 Jeffrey Bester <jbester@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and
+// to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of
+// the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package mutexes
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"bitbucket.org/jbester/sync/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+// wait until the given numbers of readers and writers are queued on the lock
+func waitForRWWaiters(lock RWLock, readers int, writers int) {
+	var rw = lock.(*rwLock)
+	for {
+		rw.lock.Lock()
+		var done = rw.waitingReaders.Len() == readers && rw.waitingWriters.Len() == writers
+		rw.lock.Unlock()
+		if done {
+			return
+		}
+		<-time.After(time.Millisecond)
+	}
+}
+
+// take the lock from a new routine, reporting the name once it is taken
+func lockAsync(lock RWLock, write bool, name string, order chan string) {
+	go func() {
+		if write {
+			lock.Lock()
+			order <- name
+			lock.Unlock()
+		} else {
+			lock.RLock()
+			order <- name
+			lock.RUnlock()
+		}
+	}()
+}
+
+func Test_RWLockReaders(t *testing.T) {
+	for _, policy := range []RWPolicy{ReaderPreferred, WriterPreferred, PhaseFair} {
+		var lock = MakeRWLock(policy)
+		assert.Equal(t, policy, lock.Policy())
+		lock.RLock()
+		assert.True(t, lock.RLockTimeout(time.Millisecond))
+		assert.Equal(t, 2, lock.Readers())
+		assert.False(t, lock.LockTimeout(time.Millisecond))
+		lock.RUnlock()
+		lock.RUnlock()
+		lock.Lock()
+		assert.True(t, lock.IsLocked())
+		assert.False(t, lock.RLockTimeout(time.Millisecond))
+		lock.Unlock()
+		assert.False(t, lock.IsLocked())
+	}
+}
+
+func Test_RWLockExclusion(t *testing.T) {
+	var lock = MakeRWLock(PhaseFair)
+	var counter = 0
+	var done = &sync.WaitGroup{}
+	done.Add(8)
+	for i := 0; i < 4; i++ {
+		go func() {
+			defer done.Done()
+			for j := 0; j < 100; j++ {
+				lock.Lock()
+				counter++
+				lock.Unlock()
+			}
+		}()
+		go func() {
+			defer done.Done()
+			for j := 0; j < 100; j++ {
+				lock.RLock()
+				_ = counter
+				lock.RUnlock()
+			}
+		}()
+	}
+	done.Wait()
+	assert.Equal(t, 400, counter)
+}
+
+func Test_RWLockReaderPreferred(t *testing.T) {
+	var lock = MakeRWLock(ReaderPreferred)
+	var order = make(chan string, 2)
+	lock.RLock()
+	lockAsync(lock, true, "writer", order)
+	waitForRWWaiters(lock, 0, 1)
+	// a new reader is admitted while the writer waits
+	assert.True(t, lock.RLockTimeout(time.Millisecond))
+	lock.RUnlock()
+	lock.RUnlock()
+	assert.Equal(t, "writer", <-order)
+}
+
+func Test_RWLockWriterPreferred(t *testing.T) {
+	var lock = MakeRWLock(WriterPreferred)
+	var order = make(chan string, 3)
+	lock.Lock()
+	lockAsync(lock, false, "reader", order)
+	waitForRWWaiters(lock, 1, 0)
+	lockAsync(lock, true, "writer", order)
+	waitForRWWaiters(lock, 1, 1)
+	lock.Unlock()
+	assert.Equal(t, "writer", <-order)
+	assert.Equal(t, "reader", <-order)
+}
+
+func Test_RWLockPhaseFair(t *testing.T) {
+	var lock = MakeRWLock(PhaseFair)
+	var order = make(chan string, 3)
+	lock.RLock()
+	lockAsync(lock, true, "first writer", order)
+	waitForRWWaiters(lock, 0, 1)
+	// readers arriving while a writer waits wait for the next read phase
+	assert.False(t, lock.RLockTimeout(time.Millisecond))
+	lockAsync(lock, false, "reader", order)
+	waitForRWWaiters(lock, 1, 1)
+	lock.RUnlock()
+	assert.Equal(t, "first writer", <-order)
+	assert.Equal(t, "reader", <-order)
+
+	lock.Lock()
+	lockAsync(lock, true, "second writer", order)
+	lockAsync(lock, false, "reader", order)
+	waitForRWWaiters(lock, 1, 1)
+	// the releasing writer admits the waiting reader ahead of the next writer
+	lock.Unlock()
+	assert.Equal(t, "reader", <-order)
+	assert.Equal(t, "second writer", <-order)
+}
+
+func Test_RWLockAbandonedWriterAdmitsReaders(t *testing.T) {
+	var fake = clock.MakeFakeClock(time.Now())
+	var lock = MakeRWLock(PhaseFair, WithClock(fake))
+	var order = make(chan string, 1)
+	lock.RLock()
+	var result = make(chan bool)
+	go func() {
+		result <- lock.LockTimeout(time.Second)
+	}()
+	waitForRWWaiters(lock, 0, 1)
+	lockAsync(lock, false, "reader", order)
+	waitForRWWaiters(lock, 1, 1)
+	fake.WaitForTimers(1)
+	fake.Advance(time.Second)
+	assert.False(t, <-result)
+	assert.Equal(t, "reader", <-order)
+	lock.RUnlock()
+}
+
+func Test_RWLockContext(t *testing.T) {
+	var lock = MakeRWLock(WriterPreferred)
+	lock.Lock()
+	var ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, context.Canceled, lock.RLockContext(ctx))
+	lock.Unlock()
+	lock.RLock()
+	assert.Equal(t, context.Canceled, lock.LockContext(ctx))
+	lock.RUnlock()
+	assert.NoError(t, lock.LockContext(context.Background()))
+	lock.Unlock()
+}
+
+func Test_RWLockUnlockNotLocked(t *testing.T) {
+	var lock = MakeRWLock(PhaseFair)
+	assert.Panics(t, func() {
+		lock.RUnlock()
+	})
+	assert.Panics(t, func() {
+		lock.Unlock()
+	})
+	lock.Lock()
+	lock.Unlock()
+	assert.Panics(t, func() {
+		lock.Unlock()
+	})
+	assert.False(t, lock.IsLocked())
+}
+
+func Test_RWLockOwnership(t *testing.T) {
+	var lock = MakeRWLock(PhaseFair, WithOwnershipCheck())
+	assert.Panics(t, func() {
+		lock.RUnlock()
+	})
+	assert.Panics(t, func() {
+		lock.Unlock()
+	})
+	lock.Lock()
+	assert.Panics(t, func() {
+		lock.Lock()
+	})
+	var result = make(chan interface{})
+	go func() {
+		defer func() {
+			result <- recover()
+		}()
+		lock.Unlock()
+	}()
+	assert.NotNil(t, <-result)
+	assert.True(t, lock.IsLocked())
+	lock.Unlock()
+}
+
+func Test_RWLockRLocker(t *testing.T) {
+	var lock = MakeRWLock(ReaderPreferred)
+	var locker = lock.RLocker()
+	locker.Lock()
+	assert.Equal(t, 1, lock.Readers())
+	locker.Unlock()
+	assert.Equal(t, 0, lock.Readers())
+	assert.Equal(t, "phase fair", PhaseFair.String())
+}
+
+func Benchmark_RWLockLockUnlock(b *testing.B) {
+	var lock = MakeRWLock(PhaseFair)
+	for n := 0; n < b.N; n++ {
+		lock.Lock()
+		lock.Unlock()
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2026 Jeffrey Bester <jbester@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and
+// to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of
+// the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package samplingports provides the SamplingPort, modelled on the ARINC 653 sampling port service.
+// A sampling port holds only the latest message written along with the time it was written.
+// Reads never block and report whether the message is fresh, that is written within the port's
+// refresh period, or stale.
+package samplingports
+
+import (
+	"sync/atomic"
+	"time"
+
+	"bitbucket.org/jbester/sync/clock"
+)
+
+// Validity of a message read from a sampling port.
+type Validity int
+
+const (
+	// No message has been written to the port.
+	Empty Validity = iota
+
+	// The message was written longer ago than the refresh period.
+	Stale
+
+	// The message was written within the refresh period.
+	Fresh
+)
+
+func (validity Validity) String() string {
+	switch validity {
+	case Empty:
+		return "empty"
+	case Stale:
+		return "stale"
+	case Fresh:
+		return "fresh"
+	}
+	return "unknown"
+}
+
+// A SamplingPort holds the latest message of type T written to it.
+type SamplingPort[T any] interface {
+	//  Write a message, replacing the previous message and its write time.
+	Write(message T)
+
+	//  Read the latest message and its validity without blocking.  The zero value is returned
+	//  with Empty validity if no message has been written.
+	Read() (T, Validity)
+
+	//  LastWrite returns the time the latest message was written, or the zero time if no message
+	//  has been written.
+	LastWrite() time.Time
+
+	//  RefreshPeriod returns the period within which a message is considered fresh.
+	RefreshPeriod() time.Duration
+}
+
+type sample[T any] struct {
+	message T
+	written time.Time
+}
+
+type samplingPort[T any] struct {
+	latest        atomic.Pointer[sample[T]]
+	refreshPeriod time.Duration
+	clock         clock.Clock
+}
+
+// Create an empty sampling port.  Messages older than the refresh period are read as stale; a
+// refresh period of zero or less means messages never become stale.
+func MakeSamplingPort[T any](refreshPeriod time.Duration, opts ...Option) SamplingPort[T] {
+	var options = makeOptions(opts)
+	return &samplingPort[T]{refreshPeriod: refreshPeriod, clock: options.clock}
+}
+
+func (port *samplingPort[T]) Write(message T) {
+	port.latest.Store(&sample[T]{message: message, written: port.clock.Now()})
+}
+
+func (port *samplingPort[T]) Read() (T, Validity) {
+	var latest = port.latest.Load()
+	if latest == nil {
+		var zero T
+		return zero, Empty
+	}
+	if port.refreshPeriod > 0 && port.clock.Since(latest.written) > port.refreshPeriod {
+		return latest.message, Stale
+	}
+	return latest.message, Fresh
+}
+
+func (port *samplingPort[T]) LastWrite() time.Time {
+	var latest = port.latest.Load()
+	if latest == nil {
+		return time.Time{}
+	}
+	return latest.written
+}
+
+func (port *samplingPort[T]) RefreshPeriod() time.Duration {
+	return port.refreshPeriod
+}
@@ -0,0 +1,102 @@
+// Copyright 2026 Jeffrey Bester <jbester@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and
+// to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of
+// the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+package samplingports
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"bitbucket.org/jbester/sync/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ReadEmpty(t *testing.T) {
+	var port = MakeSamplingPort[int](time.Second)
+	var message, validity = port.Read()
+	assert.Equal(t, Empty, validity)
+	assert.Equal(t, 0, message)
+	assert.True(t, port.LastWrite().IsZero())
+}
+
+func Test_ReadFresh(t *testing.T) {
+	var port = MakeSamplingPort[string](time.Second)
+	port.Write("first")
+	port.Write("second")
+	var message, validity = port.Read()
+	assert.Equal(t, Fresh, validity)
+	assert.Equal(t, "second", message)
+	assert.False(t, port.LastWrite().IsZero())
+}
+
+func Test_ReadStale(t *testing.T) {
+	var port = MakeSamplingPort[int](time.Millisecond)
+	port.Write(7)
+	<-time.After(time.Millisecond * 5)
+	var message, validity = port.Read()
+	assert.Equal(t, Stale, validity)
+	assert.Equal(t, 7, message)
+	// a new write refreshes the port
+	port.Write(8)
+	message, validity = port.Read()
+	assert.Equal(t, Fresh, validity)
+	assert.Equal(t, 8, message)
+}
+
+func Test_NeverStale(t *testing.T) {
+	var port = MakeSamplingPort[int](0)
+	port.Write(1)
+	<-time.After(time.Millisecond)
+	var _, validity = port.Read()
+	assert.Equal(t, Fresh, validity)
+}
+
+func Test_ConcurrentReadWrite(t *testing.T) {
+	var port = MakeSamplingPort[int](time.Second)
+	var done = &sync.WaitGroup{}
+	done.Add(2)
+	go func() {
+		defer done.Done()
+		for i := 1; i <= 1000; i++ {
+			port.Write(i)
+		}
+	}()
+	go func() {
+		defer done.Done()
+		var last = 0
+		for i := 0; i < 1000; i++ {
+			var message, _ = port.Read()
+			// messages are never read out of order
+			assert.GreaterOrEqual(t, message, last)
+			last = message
+		}
+	}()
+	done.Wait()
+	var message, _ = port.Read()
+	assert.Equal(t, 1000, message)
+}
+
+func Test_StaleFakeClock(t *testing.T) {
+	var fake = clock.MakeFakeClock(time.Now())
+	var port = MakeSamplingPort[int](time.Second, WithClock(fake))
+	port.Write(1)
+	assert.Equal(t, fake.Now(), port.LastWrite())
+	fake.Advance(time.Second)
+	var _, validity = port.Read()
+	assert.Equal(t, Fresh, validity)
+	fake.Advance(time.Nanosecond)
+	_, validity = port.Read()
+	assert.Equal(t, Stale, validity)
+}
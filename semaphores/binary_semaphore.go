@@ -19,11 +19,40 @@ type empty struct{}
 
 // Create a binary semaphore.  The semaphore can be initialized
 // to 'up' (signal) or 'down'.
-func MakeBinarySemaphore(full bool) Semaphore {
+func MakeBinarySemaphore(full bool, opts ...Option) Semaphore {
 	const max = 1
 	var initial int32 = 0
 	if full {
 		initial = 1
 	}
-	return MakeCountingSemaphore(initial, max)
+	return MakeCountingSemaphore(initial, max, opts...)
+}
+
+// Create a binary semaphore registered under the name so its statistics are exported.
+func MakeNamedBinarySemaphore(name string, full bool, opts ...Option) Semaphore {
+	var semaphore = MakeBinarySemaphore(full, opts...)
+	makeOptions(opts).registry.Register(name, semaphore)
+	return semaphore
+}
+
+// Create a fair binary semaphore.  Routines waiting to take the semaphore are
+// served in arrival order.
+func MakeFairBinarySemaphore(full bool, opts ...Option) Semaphore {
+	const max = 1
+	var initial int32 = 0
+	if full {
+		initial = 1
+	}
+	return MakeFairCountingSemaphore(initial, max, opts...)
+}
+
+// Create a priority binary semaphore.  Routines waiting to take the semaphore are
+// served highest priority first.
+func MakePriorityBinarySemaphore(full bool, opts ...Option) PrioritySemaphore {
+	const max = 1
+	var initial int32 = 0
+	if full {
+		initial = 1
+	}
+	return MakePriorityCountingSemaphore(initial, max, opts...)
 }
@@ -16,27 +16,39 @@
 package semaphores
 
 import (
+	"context"
 	"sync"
 	"sync/atomic"
 	"time"
+
+	"bitbucket.org/jbester/sync/clock"
+	"bitbucket.org/jbester/sync/metrics"
+	"bitbucket.org/jbester/sync/watchdog"
 )
 
 type countingSemaphore struct {
-	signal  chan empty
-	lock    *sync.Mutex
-	current int32
-	waiting int32
-	max     int32
+	signal   chan empty
+	lock     *sync.Mutex
+	current  int32
+	waiting  int32
+	max      int32
+	clock    clock.Clock
+	stats    *metrics.Collector
+	watchdog watchdog.Watchdog
 }
 
 // Create a counting semaphore.  The give operation increments the semaphore.
 // A take operation decrements the semaphore.
-func MakeCountingSemaphore(initial int32, max int32) Semaphore {
+func MakeCountingSemaphore(initial int32, max int32, opts ...Option) Semaphore {
+	var options = makeOptions(opts)
 	var semaphore = &countingSemaphore{
-		signal:  make(chan empty, 1),
-		lock:    &sync.Mutex{},
-		current: initial,
-		max:     max,
+		signal:   make(chan empty, 1),
+		lock:     &sync.Mutex{},
+		current:  initial,
+		max:      max,
+		clock:    options.clock,
+		stats:    metrics.MakeCollector(options.clock),
+		watchdog: options.watchdog,
 	}
 	if initial > max {
 		panic("semaphore create with initial larger than maximum")
@@ -44,11 +56,18 @@ func MakeCountingSemaphore(initial int32, max int32) Semaphore {
 	return semaphore
 }
 
-func (semaphore *countingSemaphore) tryAcquire() bool {
+// Create a counting semaphore registered under the name so its statistics are exported.
+func MakeNamedCountingSemaphore(name string, initial int32, max int32, opts ...Option) Semaphore {
+	var semaphore = MakeCountingSemaphore(initial, max, opts...)
+	makeOptions(opts).registry.Register(name, semaphore)
+	return semaphore
+}
+
+func (semaphore *countingSemaphore) tryAcquire(n int32) bool {
 	var ok = false
 	var count = semaphore.Count()
-	if count > 0 {
-		ok = atomic.CompareAndSwapInt32(&semaphore.current, count, count-1)
+	if count >= n {
+		ok = atomic.CompareAndSwapInt32(&semaphore.current, count, count-n)
 		if ok && count == semaphore.max {
 			semaphore.notify()
 		}
@@ -56,12 +75,14 @@ func (semaphore *countingSemaphore) tryAcquire() bool {
 	return ok
 }
 
-func (semaphore *countingSemaphore) tryGive() bool {
+func (semaphore *countingSemaphore) tryGive(n int32) bool {
 	var ok = false
 	var count = semaphore.Count()
-	if count < semaphore.max {
-		ok = atomic.CompareAndSwapInt32(&semaphore.current, count, count+1)
-		if ok && count == 0 {
+	// compare against the room left so a large give cannot overflow the count
+	if n <= semaphore.max-count {
+		ok = atomic.CompareAndSwapInt32(&semaphore.current, count, count+n)
+		// waiters may need more than a single unit so notify on every give
+		if ok {
 			semaphore.notify()
 		}
 	}
@@ -83,65 +104,145 @@ func (semaphore *countingSemaphore) notify() {
 	}
 }
 
-func (semaphore *countingSemaphore) wait() {
-	atomic.AddInt32(&semaphore.waiting, 1)
-	<-semaphore.signal
-	atomic.AddInt32(&semaphore.waiting, -1)
+func (semaphore *countingSemaphore) signalChannel() chan empty {
+	semaphore.lock.Lock()
+	defer semaphore.lock.Unlock()
+	return semaphore.signal
 }
 
-func (semaphore *countingSemaphore) timedWait(timeout *time.Duration) bool {
+// Block until the semaphore is signaled, the expired channel fires or the done channel is closed.
+// Returns true if signaled.  The waiting count is maintained on every path out of the wait.
+func (semaphore *countingSemaphore) block(n int32, expired <-chan time.Time, done <-chan struct{}) bool {
 	atomic.AddInt32(&semaphore.waiting, 1)
-	var ok = false
-	var start = time.Now()
+	defer atomic.AddInt32(&semaphore.waiting, -1)
+
+	var signal = semaphore.signalChannel()
+	// a give may have completed before this routine registered as a waiter
+	if semaphore.Count() >= n {
+		return true
+	}
+
 	select {
-	case <-semaphore.signal:
+	case <-signal:
+		return true
+	case <-expired:
+		return false
+	case <-done:
+		return false
+	}
+}
+
+func (semaphore *countingSemaphore) wait(n int32) {
+	semaphore.block(n, nil, nil)
+}
+
+func (semaphore *countingSemaphore) timedWait(n int32, timeout *time.Duration) bool {
+	var start = semaphore.clock.Now()
+	var timer = semaphore.clock.NewTimer(*timeout)
+	defer timer.Stop()
+	var ok = semaphore.block(n, timer.C(), nil)
+	if ok {
 		// decrement timeout by time elapsed
-		var timeElapsed = time.Now().Sub(start)
+		var timeElapsed = semaphore.clock.Since(start)
 		if timeElapsed < *timeout {
 			*timeout -= timeElapsed
 		} else {
 			*timeout = 0
 		}
-		ok = true
-
-	case <-time.After(*timeout):
 	}
-	atomic.AddInt32(&semaphore.waiting, -1)
 	return ok
 }
 
-func (semaphore *countingSemaphore) Take() {
-	var ok = false
-	for !ok {
-		// if empty wait
-		if semaphore.IsEmpty() {
-			semaphore.wait()
-		}
-
-		ok = semaphore.tryAcquire()
+func (semaphore *countingSemaphore) waitContext(n int32, ctx context.Context) error {
+	if !semaphore.block(n, nil, ctx.Done()) {
+		return ctx.Err()
 	}
+	return nil
 }
 
-func (semaphore *countingSemaphore) TryTake(timeout time.Duration) bool {
+// Take n units, calling block to wait whenever too few are available.  If block returns false the
+// take is abandoned and the wait is recorded with the failure outcome.
+func (semaphore *countingSemaphore) take(n int32, failure metrics.Outcome, block func() bool) bool {
+	checkWeight(n, semaphore.max)
+	if semaphore.tryAcquire(n) {
+		semaphore.stats.Acquired()
+		return true
+	}
+
+	var wait = semaphore.stats.BeginWait()
+	defer semaphore.watchdog.Begin("semaphore")()
 	var ok = false
 	for !ok {
-		// if empty wait
-		if semaphore.IsEmpty() {
-			if !semaphore.timedWait(&timeout) {
+		// if insufficient wait
+		if semaphore.Count() < n {
+			if !block() {
+				wait.End(failure)
 				return false
 			}
 		}
 
-		ok = semaphore.tryAcquire()
+		ok = semaphore.tryAcquire(n)
 	}
+	wait.End(metrics.Acquired)
 	return ok
 }
 
+func (semaphore *countingSemaphore) Take() {
+	semaphore.TakeN(1)
+}
+
+func (semaphore *countingSemaphore) TakeN(n int32) {
+	semaphore.take(n, metrics.Cancelled, func() bool {
+		semaphore.wait(n)
+		return true
+	})
+}
+
+func (semaphore *countingSemaphore) TryTake(timeout time.Duration) bool {
+	return semaphore.TryTakeN(1, timeout)
+}
+
+func (semaphore *countingSemaphore) TryTakeN(n int32, timeout time.Duration) bool {
+	return semaphore.take(n, metrics.TimedOut, func() bool {
+		return semaphore.timedWait(n, &timeout)
+	})
+}
+
+func (semaphore *countingSemaphore) TakeContext(ctx context.Context) error {
+	var err error
+	semaphore.take(1, metrics.Cancelled, func() bool {
+		err = semaphore.waitContext(1, ctx)
+		return err == nil
+	})
+	return err
+}
+
+func (semaphore *countingSemaphore) TakeNoWait() bool {
+	// retry while a unit is available; another routine may have changed the count
+	for semaphore.Count() >= 1 {
+		if semaphore.tryAcquire(1) {
+			semaphore.stats.Acquired()
+			return true
+		}
+	}
+	return false
+}
+
 func (semaphore *countingSemaphore) Give() bool {
+	return semaphore.GiveN(1)
+}
+
+func (semaphore *countingSemaphore) GiveN(n int32) bool {
+	if n < 1 {
+		panic("semaphore weight less than 1")
+	}
+	if n > semaphore.max {
+		return false
+	}
 	var ok = false
-	// if not full give
-	if !semaphore.IsFull() {
-		ok = semaphore.tryGive()
+	// retry while the give still fits; another routine may have changed the count
+	for !ok && n <= semaphore.max-semaphore.Count() {
+		ok = semaphore.tryGive(n)
 	}
 	return ok
 }
@@ -157,3 +258,11 @@ func (semaphore *countingSemaphore) IsFull() bool {
 func (semaphore *countingSemaphore) Count() int32 {
 	return atomic.LoadInt32(&semaphore.current)
 }
+
+func (semaphore *countingSemaphore) Stats() metrics.Stats {
+	return semaphore.stats.Stats()
+}
+
+func (semaphore *countingSemaphore) Level() (int64, int64) {
+	return int64(semaphore.Count()), int64(semaphore.max)
+}
@@ -16,6 +16,8 @@
 package semaphores
 
 import (
+	"context"
+	"math"
 	"testing"
 	"time"
 
@@ -23,6 +25,9 @@ import (
 
 	"sync"
 
+	"bitbucket.org/jbester/sync/clock"
+	"bitbucket.org/jbester/sync/metrics"
+	"bitbucket.org/jbester/sync/watchdog"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -62,6 +67,16 @@ func Test_CountingTryTake(t *testing.T) {
 	assert.True(t, semaphore.TryTake(time.Millisecond))
 }
 
+func Test_CountingTakeNoWait(t *testing.T) {
+	var semaphore = MakeCountingSemaphore(1, 1)
+	assert.True(t, semaphore.TakeNoWait())
+	assert.False(t, semaphore.TakeNoWait())
+	var stats = semaphore.Stats()
+	assert.Equal(t, uint64(1), stats.Acquisitions)
+	assert.Equal(t, uint64(0), stats.Timeouts)
+	assert.Equal(t, int64(0), stats.MaxWaiters)
+}
+
 func Test_CountingGetCount(t *testing.T) {
 	var semaphore = MakeCountingSemaphore(3, 5)
 	assert.Equal(t, int32(3), semaphore.Count())
@@ -143,3 +158,192 @@ func Test_CountingTimedTakeEmptyMultiple(t *testing.T) {
 	// verify takes
 	assert.Equal(t, int32(2), takes)
 }
+
+func Test_CountingTakeContext(t *testing.T) {
+	var semaphore = MakeCountingSemaphore(1, 1)
+	assert.NoError(t, semaphore.TakeContext(context.Background()))
+	assert.True(t, semaphore.IsEmpty())
+}
+
+func Test_CountingTakeContextCancelled(t *testing.T) {
+	var semaphore = MakeCountingSemaphore(0, 1).(*countingSemaphore)
+	var ctx, cancel = context.WithCancel(context.Background())
+	var result = make(chan error)
+	go func() {
+		result <- semaphore.TakeContext(ctx)
+	}()
+	// wait for the routine to block
+	for atomic.LoadInt32(&semaphore.waiting) == 0 {
+		<-time.After(time.Millisecond)
+	}
+	cancel()
+	assert.Equal(t, context.Canceled, <-result)
+	// verify the waiter was removed and the semaphore is untouched
+	assert.Equal(t, int32(0), atomic.LoadInt32(&semaphore.waiting))
+	assert.True(t, semaphore.Give())
+	assert.Equal(t, int32(1), semaphore.Count())
+}
+
+func Test_CountingTakeContextDeadline(t *testing.T) {
+	var semaphore = MakeCountingSemaphore(0, 1)
+	var ctx, cancel = context.WithTimeout(context.Background(), time.Millisecond*10)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, semaphore.TakeContext(ctx))
+}
+
+func Test_CountingTakeContextGiven(t *testing.T) {
+	var semaphore = MakeCountingSemaphore(0, 1)
+	var ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	go func() {
+		<-time.After(time.Millisecond * 10)
+		semaphore.Give()
+	}()
+	assert.NoError(t, semaphore.TakeContext(ctx))
+	assert.True(t, semaphore.IsEmpty())
+}
+
+func Test_CountingTakeN(t *testing.T) {
+	var semaphore = MakeCountingSemaphore(5, 5)
+	semaphore.TakeN(3)
+	assert.Equal(t, int32(2), semaphore.Count())
+}
+
+func Test_CountingTryTakeNInsufficient(t *testing.T) {
+	var semaphore = MakeCountingSemaphore(2, 4)
+	assert.False(t, semaphore.TryTakeN(3, time.Millisecond))
+	// verify nothing was partially taken
+	assert.Equal(t, int32(2), semaphore.Count())
+}
+
+func Test_CountingTakeNInvalid(t *testing.T) {
+	var semaphore = MakeCountingSemaphore(2, 4)
+	assert.Panics(t, func() {
+		semaphore.TakeN(5)
+	})
+	assert.Panics(t, func() {
+		semaphore.TakeN(0)
+	})
+}
+
+func Test_CountingGiveN(t *testing.T) {
+	var semaphore = MakeCountingSemaphore(1, 4)
+	assert.True(t, semaphore.GiveN(3))
+	assert.True(t, semaphore.IsFull())
+	assert.False(t, semaphore.GiveN(1))
+}
+
+func Test_CountingGiveNOverflow(t *testing.T) {
+	var semaphore = MakeCountingSemaphore(2, 4)
+	assert.False(t, semaphore.GiveN(3))
+	// verify nothing was partially given
+	assert.Equal(t, int32(2), semaphore.Count())
+}
+
+func Test_CountingGiveNOversized(t *testing.T) {
+	var semaphore = MakeCountingSemaphore(1, 10)
+	assert.False(t, semaphore.GiveN(math.MaxInt32))
+	assert.False(t, semaphore.GiveN(11))
+	assert.Equal(t, int32(1), semaphore.Count())
+	assert.True(t, semaphore.TryTake(0))
+}
+
+func Test_CountingTakeNWaitsForAllUnits(t *testing.T) {
+	var semaphore = MakeCountingSemaphore(0, 4)
+	var done = make(chan empty)
+	go func() {
+		semaphore.TakeN(3)
+		close(done)
+	}()
+	semaphore.GiveN(2)
+	select {
+	case <-done:
+		assert.Fail(t, "take completed with insufficient units")
+	case <-time.After(time.Millisecond * 10):
+	}
+	semaphore.Give()
+	<-done
+	assert.True(t, semaphore.IsEmpty())
+}
+
+func Test_CountingTakeNLargeJobs(t *testing.T) {
+	var semaphore = MakeCountingSemaphore(4, 4)
+	var done = &sync.WaitGroup{}
+	var worker = func() {
+		defer done.Done()
+		for i := 0; i < 100; i++ {
+			semaphore.TakeN(3)
+			semaphore.GiveN(3)
+		}
+	}
+	done.Add(2)
+	go worker()
+	go worker()
+	done.Wait()
+	assert.True(t, semaphore.IsFull())
+}
+
+func Test_CountingTryTakeFakeClock(t *testing.T) {
+	var fake = clock.MakeFakeClock(time.Now())
+	var semaphore = MakeCountingSemaphore(0, 1, WithClock(fake))
+	var result = make(chan bool)
+	go func() {
+		result <- semaphore.TryTake(time.Hour)
+	}()
+	fake.WaitForTimers(1)
+	fake.Advance(time.Hour)
+	assert.False(t, <-result)
+}
+
+func Test_CountingStats(t *testing.T) {
+	var fake = clock.MakeFakeClock(time.Now())
+	var semaphore = MakeCountingSemaphore(1, 1, WithClock(fake))
+	semaphore.Take()
+	var result = make(chan bool)
+	go func() {
+		result <- semaphore.TryTake(time.Second)
+	}()
+	fake.WaitForTimers(1)
+	assert.Equal(t, int64(1), semaphore.Stats().Waiters)
+	fake.Advance(time.Second)
+	assert.False(t, <-result)
+
+	var stats = semaphore.Stats()
+	assert.Equal(t, uint64(1), stats.Acquisitions)
+	assert.Equal(t, uint64(1), stats.Timeouts)
+	assert.Equal(t, int64(0), stats.Waiters)
+	assert.Equal(t, int64(1), stats.MaxWaiters)
+	assert.Equal(t, time.Second, stats.TotalWaitTime)
+}
+
+func Test_NamedCountingSemaphore(t *testing.T) {
+	var registry = metrics.MakeRegistry()
+	var semaphore = MakeNamedCountingSemaphore("pool", 3, 5, WithRegistry(registry))
+	semaphore.Take()
+	var source, ok = registry.Lookup("pool")
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1), source.Stats().Acquisitions)
+	var count, capacity = source.(metrics.Level).Level()
+	assert.Equal(t, int64(2), count)
+	assert.Equal(t, int64(5), capacity)
+}
+
+func Test_CountingWatchdog(t *testing.T) {
+	var dog = watchdog.MakeWatchdog(time.Hour)
+	defer dog.Stop()
+	var semaphore = MakeCountingSemaphore(0, 1, WithWatchdog(dog))
+	var done = make(chan empty)
+	go func() {
+		semaphore.Take()
+		close(done)
+	}()
+	for len(dog.Waits()) == 0 {
+		<-time.After(time.Millisecond)
+	}
+	var wait = dog.Waits()[0]
+	assert.Equal(t, "semaphore", wait.Kind)
+	assert.Contains(t, string(wait.Stack), "Test_CountingWatchdog")
+	semaphore.Give()
+	<-done
+	assert.Empty(t, dog.Waits())
+}
@@ -0,0 +1,302 @@
+// Copyright 2026 Jeffrey Bester <jbester@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and
+// to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of
+// the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package semaphores
+
+import (
+	"container/list"
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"bitbucket.org/jbester/sync/clock"
+	"bitbucket.org/jbester/sync/metrics"
+	"bitbucket.org/jbester/sync/tasks"
+	"bitbucket.org/jbester/sync/watchdog"
+)
+
+type fairWaiter struct {
+	n        int32
+	priority int
+	ready    chan empty
+	element  *list.Element
+	granted  bool
+}
+
+type fairSemaphore struct {
+	lock       *sync.Mutex
+	waiters    *list.List
+	byPriority bool
+	current    int32
+	max        int32
+	clock      clock.Clock
+	stats      *metrics.Collector
+	watchdog   watchdog.Watchdog
+}
+
+// Create a fair counting semaphore.  Routines waiting to take the semaphore are served strictly
+// in arrival order and each give wakes only the waiters it can satisfy.  A waiter requesting
+// several units blocks the waiters queued behind it until its request can be met.
+func MakeFairCountingSemaphore(initial int32, max int32, opts ...Option) Semaphore {
+	if initial > max {
+		panic("semaphore create with initial larger than maximum")
+	}
+	var options = makeOptions(opts)
+	return &fairSemaphore{
+		lock:     &sync.Mutex{},
+		waiters:  list.New(),
+		current:  initial,
+		max:      max,
+		clock:    options.clock,
+		stats:    metrics.MakeCollector(options.clock),
+		watchdog: options.watchdog,
+	}
+}
+
+// Create a priority counting semaphore.  Routines waiting to take the semaphore are served highest
+// priority first; routines of equal priority are served in arrival order.  Take, TryTake and
+// TakeContext wait at DefaultPriority.
+func MakePriorityCountingSemaphore(initial int32, max int32, opts ...Option) PrioritySemaphore {
+	var semaphore = MakeFairCountingSemaphore(initial, max, opts...).(*fairSemaphore)
+	semaphore.byPriority = true
+	return semaphore
+}
+
+// Hand units to the waiters at the head of the queue while there are enough available.
+// Must be called with the lock held.
+func (semaphore *fairSemaphore) dispatch() {
+	for front := semaphore.waiters.Front(); front != nil; front = semaphore.waiters.Front() {
+		var waiter = front.Value.(*fairWaiter)
+		if semaphore.current < waiter.n {
+			break
+		}
+		semaphore.current -= waiter.n
+		semaphore.waiters.Remove(front)
+		waiter.element = nil
+		waiter.granted = true
+		close(waiter.ready)
+	}
+}
+
+// Waits with this timeout do not expire.
+const forever = time.Duration(math.MaxInt64)
+
+// Take n units, queueing behind any existing waiters, until the timeout expires or the done
+// channel is closed.  If a task is given it waits at the task's priority, which is followed as it
+// changes, in place of the given priority.  Returns true if the units were taken.
+func (semaphore *fairSemaphore) acquire(n int32, priority int, task tasks.Task, timeout time.Duration, done <-chan struct{}) bool {
+	checkWeight(n, semaphore.max)
+	var waiter = &fairWaiter{n: n, priority: priority}
+	if task != nil {
+		// watch before reading the priority so no change is missed; watching takes the task's
+		// lock so it is done before taking the semaphore's
+		defer task.Watch(func() {
+			semaphore.reprioritize(waiter, task)
+		})()
+	}
+	semaphore.lock.Lock()
+	if semaphore.waiters.Len() == 0 && semaphore.current >= n {
+		semaphore.current -= n
+		semaphore.lock.Unlock()
+		semaphore.stats.Acquired()
+		return true
+	}
+	if task != nil {
+		waiter.priority = task.Priority()
+	}
+	waiter.ready = make(chan empty)
+	semaphore.enqueue(waiter)
+	semaphore.lock.Unlock()
+	var wait = semaphore.stats.BeginWait()
+	defer semaphore.watchdog.Begin("semaphore")()
+
+	var expired <-chan time.Time
+	if timeout != forever {
+		var timer = semaphore.clock.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C()
+	}
+	var failure = metrics.TimedOut
+	select {
+	case <-waiter.ready:
+		wait.End(metrics.Acquired)
+		return true
+	case <-expired:
+	case <-done:
+		failure = metrics.Cancelled
+	}
+	if semaphore.abandon(waiter) {
+		wait.End(metrics.Acquired)
+		return true
+	}
+	wait.End(failure)
+	return false
+}
+
+// Queue a waiter behind all waiters of the same or higher priority.  Without priority ordering
+// this is the tail of the queue.  Must be called with the lock held.
+func (semaphore *fairSemaphore) enqueue(waiter *fairWaiter) {
+	if semaphore.byPriority {
+		for e := semaphore.waiters.Back(); e != nil; e = e.Prev() {
+			if e.Value.(*fairWaiter).priority >= waiter.priority {
+				waiter.element = semaphore.waiters.InsertAfter(waiter, e)
+				return
+			}
+		}
+		waiter.element = semaphore.waiters.PushFront(waiter)
+		return
+	}
+	waiter.element = semaphore.waiters.PushBack(waiter)
+}
+
+// Remove a waiter that stopped waiting.  Returns true if the units were granted before the
+// waiter could be removed.
+func (semaphore *fairSemaphore) abandon(waiter *fairWaiter) bool {
+	semaphore.lock.Lock()
+	defer semaphore.lock.Unlock()
+	if waiter.granted {
+		return true
+	}
+	semaphore.waiters.Remove(waiter.element)
+	waiter.element = nil
+	// the abandoned waiter may have been holding up the rest of the queue
+	semaphore.dispatch()
+	return false
+}
+
+// Requeue a waiter after its task's priority changed.
+func (semaphore *fairSemaphore) reprioritize(waiter *fairWaiter, task tasks.Task) {
+	semaphore.lock.Lock()
+	defer semaphore.lock.Unlock()
+	if !semaphore.byPriority || waiter.element == nil {
+		return
+	}
+	semaphore.waiters.Remove(waiter.element)
+	waiter.priority = task.Priority()
+	semaphore.enqueue(waiter)
+	// a waiter for fewer units may now be at the head of the queue
+	semaphore.dispatch()
+}
+
+func (semaphore *fairSemaphore) numWaiting() int {
+	semaphore.lock.Lock()
+	defer semaphore.lock.Unlock()
+	return semaphore.waiters.Len()
+}
+
+func (semaphore *fairSemaphore) Take() {
+	semaphore.TakePriority(DefaultPriority)
+}
+
+func (semaphore *fairSemaphore) TakeN(n int32) {
+	semaphore.acquire(n, DefaultPriority, nil, forever, nil)
+}
+
+func (semaphore *fairSemaphore) TakePriority(priority int) {
+	semaphore.acquire(1, priority, nil, forever, nil)
+}
+
+func (semaphore *fairSemaphore) TryTake(timeout time.Duration) bool {
+	return semaphore.TryTakePriority(DefaultPriority, timeout)
+}
+
+func (semaphore *fairSemaphore) TryTakeN(n int32, timeout time.Duration) bool {
+	return semaphore.acquire(n, DefaultPriority, nil, timeout, nil)
+}
+
+func (semaphore *fairSemaphore) TryTakePriority(priority int, timeout time.Duration) bool {
+	return semaphore.acquire(1, priority, nil, timeout, nil)
+}
+
+func (semaphore *fairSemaphore) TakeContext(ctx context.Context) error {
+	return semaphore.TakePriorityContext(ctx, DefaultPriority)
+}
+
+func (semaphore *fairSemaphore) TakePriorityContext(ctx context.Context, priority int) error {
+	if !semaphore.acquire(1, priority, nil, forever, ctx.Done()) {
+		return ctx.Err()
+	}
+	return nil
+}
+
+func (semaphore *fairSemaphore) TakeTask(task tasks.Task) {
+	semaphore.acquire(1, DefaultPriority, task, forever, nil)
+}
+
+func (semaphore *fairSemaphore) TryTakeTask(task tasks.Task, timeout time.Duration) bool {
+	return semaphore.acquire(1, DefaultPriority, task, timeout, nil)
+}
+
+func (semaphore *fairSemaphore) TakeTaskContext(ctx context.Context, task tasks.Task) error {
+	if !semaphore.acquire(1, DefaultPriority, task, forever, ctx.Done()) {
+		return ctx.Err()
+	}
+	return nil
+}
+
+func (semaphore *fairSemaphore) TakeNoWait() bool {
+	semaphore.lock.Lock()
+	// units left while routines wait are reserved for the routine at the head of the queue
+	if semaphore.waiters.Len() > 0 || semaphore.current < 1 {
+		semaphore.lock.Unlock()
+		return false
+	}
+	semaphore.current--
+	semaphore.lock.Unlock()
+	semaphore.stats.Acquired()
+	return true
+}
+
+func (semaphore *fairSemaphore) Give() bool {
+	return semaphore.GiveN(1)
+}
+
+func (semaphore *fairSemaphore) GiveN(n int32) bool {
+	if n < 1 {
+		panic("semaphore weight less than 1")
+	}
+	semaphore.lock.Lock()
+	defer semaphore.lock.Unlock()
+	// compare against the room left so a large give cannot overflow the count
+	if n > semaphore.max-semaphore.current {
+		return false
+	}
+	semaphore.current += n
+	semaphore.dispatch()
+	return true
+}
+
+func (semaphore *fairSemaphore) IsEmpty() bool {
+	return semaphore.Count() == 0
+}
+
+func (semaphore *fairSemaphore) IsFull() bool {
+	return semaphore.Count() == semaphore.max
+}
+
+func (semaphore *fairSemaphore) Count() int32 {
+	semaphore.lock.Lock()
+	defer semaphore.lock.Unlock()
+	return semaphore.current
+}
+
+func (semaphore *fairSemaphore) Stats() metrics.Stats {
+	return semaphore.stats.Stats()
+}
+
+func (semaphore *fairSemaphore) Level() (int64, int64) {
+	return int64(semaphore.Count()), int64(semaphore.max)
+}
@@ -0,0 +1,303 @@
+// Copyright 2026 Jeffrey Bester <jbester@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and
+// to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of
+// the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package semaphores
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"bitbucket.org/jbester/sync/clock"
+	"bitbucket.org/jbester/sync/tasks"
+	"github.com/stretchr/testify/assert"
+)
+
+// wait until the given number of routines are queued on the semaphore
+func waitForFairWaiters(semaphore *fairSemaphore, count int) {
+	for semaphore.numWaiting() != count {
+		<-time.After(time.Millisecond)
+	}
+}
+
+func Test_FairLockUnlock(t *testing.T) {
+	var semaphore = MakeFairCountingSemaphore(0, 1)
+	assert.True(t, semaphore.Give())
+	assert.True(t, semaphore.IsFull())
+	semaphore.Take()
+	assert.True(t, semaphore.IsEmpty())
+}
+
+func Test_FairCreateInvalid(t *testing.T) {
+	assert.Panics(t, func() {
+		MakeFairCountingSemaphore(2, 1)
+	})
+}
+
+func Test_FairGiveFull(t *testing.T) {
+	var semaphore = MakeFairBinarySemaphore(true)
+	assert.False(t, semaphore.Give())
+}
+
+func Test_FairGiveOversized(t *testing.T) {
+	var semaphore = MakeFairCountingSemaphore(1, 10)
+	assert.False(t, semaphore.GiveN(math.MaxInt32))
+	assert.Equal(t, int32(1), semaphore.Count())
+	assert.True(t, semaphore.TryTake(0))
+}
+
+func Test_FairTakeEmpty(t *testing.T) {
+	var semaphore = MakeFairCountingSemaphore(0, 1).(*fairSemaphore)
+	assert.False(t, semaphore.TryTake(time.Millisecond))
+	assert.Equal(t, 0, semaphore.numWaiting())
+}
+
+func Test_FairOrdering(t *testing.T) {
+	var semaphore = MakeFairCountingSemaphore(0, 1).(*fairSemaphore)
+	var order = make(chan int, 3)
+	for i := 0; i < 3; i++ {
+		var id = i
+		go func() {
+			semaphore.Take()
+			order <- id
+		}()
+		waitForFairWaiters(semaphore, i+1)
+	}
+	for i := 0; i < 3; i++ {
+		semaphore.Give()
+		assert.Equal(t, i, <-order)
+	}
+}
+
+func Test_FairWakesOneWaiterPerUnit(t *testing.T) {
+	var semaphore = MakeFairCountingSemaphore(0, 3).(*fairSemaphore)
+	for i := 0; i < 3; i++ {
+		go semaphore.Take()
+	}
+	waitForFairWaiters(semaphore, 3)
+	semaphore.GiveN(2)
+	waitForFairWaiters(semaphore, 1)
+	assert.True(t, semaphore.IsEmpty())
+	semaphore.Give()
+	waitForFairWaiters(semaphore, 0)
+}
+
+func Test_FairWeightedHeadBlocksQueue(t *testing.T) {
+	var semaphore = MakeFairCountingSemaphore(0, 2).(*fairSemaphore)
+	var done = make(chan int, 2)
+	go func() {
+		semaphore.TakeN(2)
+		done <- 2
+	}()
+	waitForFairWaiters(semaphore, 1)
+	go func() {
+		semaphore.Take()
+		done <- 1
+	}()
+	waitForFairWaiters(semaphore, 2)
+	// a single unit is not given to the later, smaller request
+	semaphore.Give()
+	assert.Equal(t, int32(1), semaphore.Count())
+	semaphore.Give()
+	assert.Equal(t, 2, <-done)
+	semaphore.Give()
+	assert.Equal(t, 1, <-done)
+}
+
+func Test_FairTakeNoWait(t *testing.T) {
+	var semaphore = MakeFairCountingSemaphore(1, 2).(*fairSemaphore)
+	assert.True(t, semaphore.TakeNoWait())
+	assert.False(t, semaphore.TakeNoWait())
+	assert.Equal(t, 0, semaphore.numWaiting())
+
+	var done = make(chan struct{})
+	go func() {
+		semaphore.TakeN(2)
+		close(done)
+	}()
+	waitForFairWaiters(semaphore, 1)
+	semaphore.Give()
+	// the unit is reserved for the waiting routine
+	assert.False(t, semaphore.TakeNoWait())
+	semaphore.Give()
+	<-done
+	var stats = semaphore.Stats()
+	assert.Equal(t, uint64(0), stats.Timeouts)
+	assert.Equal(t, int64(1), stats.MaxWaiters)
+}
+
+func Test_FairTimeoutUnblocksQueue(t *testing.T) {
+	var semaphore = MakeFairCountingSemaphore(1, 2).(*fairSemaphore)
+	var done = make(chan bool)
+	go func() {
+		done <- semaphore.TryTakeN(2, time.Millisecond*20)
+	}()
+	waitForFairWaiters(semaphore, 1)
+	go func() {
+		semaphore.Take()
+		done <- true
+	}()
+	waitForFairWaiters(semaphore, 2)
+	// when the head times out the next waiter is served from the available unit
+	assert.False(t, <-done)
+	assert.True(t, <-done)
+	assert.True(t, semaphore.IsEmpty())
+}
+
+func Test_FairTakeContextCancelled(t *testing.T) {
+	var semaphore = MakeFairCountingSemaphore(0, 1).(*fairSemaphore)
+	var ctx, cancel = context.WithCancel(context.Background())
+	var result = make(chan error)
+	go func() {
+		result <- semaphore.TakeContext(ctx)
+	}()
+	waitForFairWaiters(semaphore, 1)
+	cancel()
+	assert.Equal(t, context.Canceled, <-result)
+	assert.Equal(t, 0, semaphore.numWaiting())
+	semaphore.Give()
+	assert.True(t, semaphore.IsFull())
+}
+
+func Test_PriorityOrdering(t *testing.T) {
+	var semaphore = MakePriorityCountingSemaphore(0, 1).(*fairSemaphore)
+	var order = make(chan int, 4)
+	var priorities = []int{1, 5, DefaultPriority, 5}
+	for i, priority := range priorities {
+		var prio = priority
+		go func() {
+			semaphore.TakePriority(prio)
+			order <- prio
+		}()
+		waitForFairWaiters(semaphore, i+1)
+	}
+	for _, expected := range []int{5, 5, 1, DefaultPriority} {
+		semaphore.Give()
+		assert.Equal(t, expected, <-order)
+	}
+}
+
+func Test_PriorityEqualIsFifo(t *testing.T) {
+	var semaphore = MakePriorityBinarySemaphore(false).(*fairSemaphore)
+	var order = make(chan int, 3)
+	for i := 0; i < 3; i++ {
+		var id = i
+		go func() {
+			semaphore.TakePriority(3)
+			order <- id
+		}()
+		waitForFairWaiters(semaphore, i+1)
+	}
+	for i := 0; i < 3; i++ {
+		semaphore.Give()
+		assert.Equal(t, i, <-order)
+	}
+}
+
+func Test_PriorityTryTakeTimeout(t *testing.T) {
+	var semaphore = MakePriorityCountingSemaphore(0, 1).(*fairSemaphore)
+	var done = make(chan int, 2)
+	go func() {
+		semaphore.TakePriority(1)
+		done <- 1
+	}()
+	waitForFairWaiters(semaphore, 1)
+	// the higher priority waiter gives up before the semaphore is given
+	assert.False(t, semaphore.TryTakePriority(10, time.Millisecond*10))
+	semaphore.Give()
+	assert.Equal(t, 1, <-done)
+}
+
+func Test_FairTryTakeFakeClock(t *testing.T) {
+	var fake = clock.MakeFakeClock(time.Now())
+	var semaphore = MakeFairCountingSemaphore(0, 1, WithClock(fake)).(*fairSemaphore)
+	var result = make(chan bool)
+	go func() {
+		result <- semaphore.TryTake(time.Hour)
+	}()
+	fake.WaitForTimers(1)
+	fake.Advance(time.Minute)
+	semaphore.Give()
+	assert.True(t, <-result)
+	assert.Equal(t, 0, fake.Timers())
+
+	go func() {
+		result <- semaphore.TryTake(time.Hour)
+	}()
+	fake.WaitForTimers(1)
+	fake.Advance(time.Hour)
+	assert.False(t, <-result)
+	assert.Equal(t, 0, semaphore.numWaiting())
+}
+
+func Test_FairStats(t *testing.T) {
+	var semaphore = MakeFairCountingSemaphore(0, 1).(*fairSemaphore)
+	var ctx, cancel = context.WithCancel(context.Background())
+	var result = make(chan error)
+	go func() {
+		result <- semaphore.TakeContext(ctx)
+	}()
+	waitForFairWaiters(semaphore, 1)
+	cancel()
+	<-result
+	var taken = make(chan empty)
+	go func() {
+		semaphore.Take()
+		close(taken)
+	}()
+	waitForFairWaiters(semaphore, 1)
+	semaphore.Give()
+	<-taken
+
+	var stats = semaphore.Stats()
+	assert.Equal(t, uint64(1), stats.Cancellations)
+	assert.Equal(t, uint64(1), stats.Acquisitions)
+	assert.Equal(t, int64(1), stats.MaxWaiters)
+}
+
+func Test_PriorityTask(t *testing.T) {
+	var semaphore = MakePriorityCountingSemaphore(0, 1).(*fairSemaphore)
+	var order = make(chan int, 3)
+	var low, high = tasks.MakeTask(1), tasks.MakeTask(5)
+	var waiting = []tasks.Task{low, high, tasks.MakeTask(3)}
+	for i, task := range waiting {
+		var waiter = task
+		var id = i
+		go func() {
+			semaphore.TakeTask(waiter)
+			order <- id
+		}()
+		waitForFairWaiters(semaphore, i+1)
+	}
+	// raising the priority of a waiting task moves it ahead in the queue
+	low.SetPriority(10)
+	for _, expected := range []int{0, 1, 2} {
+		semaphore.Give()
+		assert.Equal(t, expected, <-order)
+	}
+}
+
+func Test_PriorityTryTakeTaskTimeout(t *testing.T) {
+	var semaphore = MakePriorityCountingSemaphore(0, 1).(*fairSemaphore)
+	var task = tasks.MakeTask(1)
+	assert.False(t, semaphore.TryTakeTask(task, time.Millisecond))
+	assert.Equal(t, 0, semaphore.numWaiting())
+	// changing the priority after the wait ends has no effect on the queue
+	task.SetPriority(2)
+	assert.Equal(t, 0, semaphore.numWaiting())
+	semaphore.Give()
+	assert.NoError(t, semaphore.TakeTaskContext(context.Background(), task))
+}
@@ -0,0 +1,88 @@
+// Copyright 2026 Jeffrey Bester <jbester@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and
+// to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of
+// the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package semaphores
+
+import (
+	"time"
+
+	"bitbucket.org/jbester/sync/clock"
+	"bitbucket.org/jbester/sync/metrics"
+	"bitbucket.org/jbester/sync/watchdog"
+)
+
+// Option configures optional behaviour of a semaphore when it is created.
+type Option func(*options)
+
+type options struct {
+	clock        clock.Clock
+	registry     *metrics.Registry
+	watchdog     watchdog.Watchdog
+	violation    func(error)
+	abandonCheck time.Duration
+}
+
+// WithClock makes a semaphore use the clock for timed operations in place of the system clock.
+func WithClock(c clock.Clock) Option {
+	return func(opts *options) {
+		opts.clock = c
+	}
+}
+
+// WithRegistry registers a semaphore created by a named constructor, such as
+// MakeNamedCountingSemaphore, in the registry in place of metrics.DefaultRegistry.  Other
+// constructors ignore the option.
+func WithRegistry(registry *metrics.Registry) Option {
+	return func(opts *options) {
+		opts.registry = registry
+	}
+}
+
+// WithWatchdog makes a semaphore record its blocked waits with the watchdog.
+func WithWatchdog(w watchdog.Watchdog) Option {
+	return func(opts *options) {
+		opts.watchdog = w
+	}
+}
+
+// WithViolationHandler makes a tracked semaphore pass ownership violations and abandoned permits to
+// the handler in place of panicking.  Semaphores that do not track ownership ignore the option.
+func WithViolationHandler(handler func(error)) Option {
+	return func(opts *options) {
+		opts.violation = handler
+	}
+}
+
+// WithAbandonCheck makes a tracked semaphore check for abandoned permits at the interval and pass
+// an *AbandonedError for each abandoned holder to the violation handler.  A holder is reported
+// once.  The check inspects every running routine and is expensive.  Semaphores that do not track
+// ownership ignore the option.
+func WithAbandonCheck(interval time.Duration) Option {
+	return func(opts *options) {
+		opts.abandonCheck = interval
+	}
+}
+
+func makeOptions(opts []Option) options {
+	var result = options{
+		clock:    clock.Real(),
+		registry: metrics.DefaultRegistry,
+		watchdog: watchdog.Disabled(),
+	}
+	for _, opt := range opts {
+		opt(&result)
+	}
+	return result
+}
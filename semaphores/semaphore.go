@@ -16,9 +16,18 @@
 // Package semaphores provides a go implementation of binary and counting semaphores.
 // The underlying implementation is built on the channel primitive for goroutine notifications and atomic operations for
 // for the fast path.
+//
+// Fair and priority semaphores queue waiting routines and serve them in arrival or priority order
+// respectively, similar to the FIFO and priority queuing disciplines of pSOS and ARINC 653.
 package semaphores
 
-import "time"
+import (
+	"context"
+	"time"
+
+	"bitbucket.org/jbester/sync/metrics"
+	"bitbucket.org/jbester/sync/tasks"
+)
 
 // Semaphore interface.
 type Semaphore interface {
@@ -29,9 +38,31 @@ type Semaphore interface {
 	// or the semaphore becomes available
 	TryTake(timeout time.Duration) bool
 
+	// Take (decrement) a semaphore.  Routine will block until the semaphore becomes available
+	// or the context is cancelled or its deadline passes.  Returns the context's error if the
+	// semaphore was not taken.
+	TakeContext(ctx context.Context) error
+
+	// Take (decrement) a semaphore only if it is available now.  Never waits or queues behind
+	// waiting routines; returns false if the semaphore was not taken.
+	TakeNoWait() bool
+
+	// Take (decrement) n units of a semaphore at once.  Routine will block until all n
+	// units are available; units are never partially taken.  Panics if n is less than 1
+	// or larger than the semaphore maximum.
+	TakeN(n int32)
+
+	// Take (decrement) n units of a semaphore at once.  Routine will block until the timeout
+	// has occurred or all n units become available.
+	TryTakeN(n int32, timeout time.Duration) bool
+
 	// Release (increment) the semaphore.  Returns false if semaphore is signal.
 	Give() bool
 
+	// Release (increment) n units of the semaphore at once.  Returns false, releasing nothing,
+	// if the release would exceed the semaphore maximum.
+	GiveN(n int32) bool
+
 	// Test if the semaphore is signal.
 	IsFull() bool
 
@@ -40,4 +71,50 @@ type Semaphore interface {
 
 	//  Returns the count of a semaphore.
 	Count() int32
+
+	//  Returns a snapshot of the semaphore's statistics.  Takes are counted as acquisitions;
+	//  waits are timed from when a take first finds too few units available.
+	Stats() metrics.Stats
+}
+
+// Priority given to waiters that take a priority semaphore without specifying one.
+const DefaultPriority = 0
+
+// PrioritySemaphore is a semaphore whose waiting routines are queued by priority in the manner of
+// pSOS and ARINC 653 priority queuing.  When the semaphore is given the highest priority waiter
+// is served first; larger values indicate higher priority.
+type PrioritySemaphore interface {
+	Semaphore
+
+	// Take (decrement) a semaphore waiting at the given priority.  Routine will block until
+	// the semaphore is available.
+	TakePriority(priority int)
+
+	// Take (decrement) a semaphore waiting at the given priority.  Routine will block until the
+	// timeout has occurred or the semaphore becomes available.
+	TryTakePriority(priority int, timeout time.Duration) bool
+
+	// Take (decrement) a semaphore waiting at the given priority.  Routine will block until the
+	// semaphore becomes available or the context is done.
+	TakePriorityContext(ctx context.Context, priority int) error
+
+	// Take (decrement) a semaphore waiting at the task's priority.  The routine is requeued if
+	// the task's priority changes while it waits, for example when the task inherits a
+	// priority.  Routine will block until the semaphore is available.
+	TakeTask(task tasks.Task)
+
+	// Take (decrement) a semaphore waiting at the task's priority.  Routine will block until the
+	// timeout has occurred or the semaphore becomes available.
+	TryTakeTask(task tasks.Task, timeout time.Duration) bool
+
+	// Take (decrement) a semaphore waiting at the task's priority.  Routine will block until the
+	// semaphore becomes available or the context is done.
+	TakeTaskContext(ctx context.Context, task tasks.Task) error
+}
+
+// Panics if a request for n units cannot be satisfied by a semaphore with the given maximum.
+func checkWeight(n int32, max int32) {
+	if n < 1 || n > max {
+		panic("semaphore weight outside the range 1 to maximum")
+	}
 }
@@ -0,0 +1,331 @@
+// Copyright 2026 Jeffrey Bester <jbester@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and
+// to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of
+// the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package semaphores
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"bitbucket.org/jbester/sync/internal/goroutine"
+)
+
+// A Holder records permits of a tracked semaphore taken by a routine.
+type Holder struct {
+	// Id of the routine that took the permits, as shown in a goroutine dump.
+	Goroutine uint64
+
+	// Number of permits held.
+	Permits int32
+
+	// File and line of the call that took the permits.
+	Site string
+
+	// When the permits were taken.
+	Taken time.Time
+}
+
+// An OwnershipError reports a give that releases more permits than are held.
+type OwnershipError struct {
+	// Id of the routine that gave the permits.
+	Goroutine uint64
+
+	// File and line of the call that gave the permits.
+	Site string
+
+	// Permits given and permits held at the time of the give.
+	Given int32
+	Held  int32
+}
+
+func (err *OwnershipError) Error() string {
+	return fmt.Sprintf("semaphore: goroutine %d at %s gave %d permits but only %d are held",
+		err.Goroutine, err.Site, err.Given, err.Held)
+}
+
+// An AbandonedError reports permits held by a routine that has exited without giving them back.
+type AbandonedError struct {
+	Holder Holder
+}
+
+func (err *AbandonedError) Error() string {
+	return fmt.Sprintf("semaphore: goroutine %d exited holding %d permits taken at %s",
+		err.Holder.Goroutine, err.Holder.Permits, err.Holder.Site)
+}
+
+// TrackedSemaphore is a semaphore that records which routine and call site took each permit.  It
+// is intended for debugging semaphores that guard a pool of resources, where every give returns a
+// permit taken earlier.
+type TrackedSemaphore interface {
+	Semaphore
+
+	//  Holders returns the routines holding permits, oldest first.
+	Holders() []Holder
+
+	//  Abandoned returns the holders whose routine has exited without giving back its permits.
+	//  It inspects every running routine and is expensive.
+	Abandoned() []Holder
+
+	//  Stop the periodic abandonment check set with WithAbandonCheck.
+	Stop()
+}
+
+type trackedSemaphore struct {
+	*countingSemaphore
+	lock      *sync.Mutex
+	holders   *list.List
+	held      int32
+	reported  map[*Holder]bool
+	violation func(error)
+	stop      chan struct{}
+	stopOnce  *sync.Once
+}
+
+// Create a counting semaphore that tracks the ownership of its permits.  A give that releases more
+// permits than are held is an ownership violation; it is passed to the handler set with
+// WithViolationHandler or, by default, panics with an *OwnershipError; if the handler returns, the
+// give releases nothing and returns false.  The initial permits are not held by any routine.
+//
+// With WithAbandonCheck the semaphore checks for abandoned permits from its own routine until
+// stopped, passing an *AbandonedError for each abandoned holder to the same handler.
+func MakeTrackedCountingSemaphore(initial int32, max int32, opts ...Option) TrackedSemaphore {
+	var options = makeOptions(opts)
+	var violation = options.violation
+	if violation == nil {
+		violation = func(err error) {
+			panic(err)
+		}
+	}
+	var semaphore = &trackedSemaphore{
+		countingSemaphore: MakeCountingSemaphore(initial, max, opts...).(*countingSemaphore),
+		lock:              &sync.Mutex{},
+		holders:           list.New(),
+		reported:          make(map[*Holder]bool),
+		violation:         violation,
+		stop:              make(chan struct{}),
+		stopOnce:          &sync.Once{},
+	}
+	if options.abandonCheck > 0 {
+		go semaphore.monitor(options.abandonCheck)
+	}
+	return semaphore
+}
+
+// Returns the file and line of the first caller outside the semaphore.
+func callSite() string {
+	var pcs = make([]uintptr, 16)
+	var frames = runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	for {
+		var frame, more = frames.Next()
+		if !strings.Contains(frame.Function, "(*trackedSemaphore)") || !more {
+			return frame.File + ":" + strconv.Itoa(frame.Line)
+		}
+	}
+}
+
+// Record n permits taken by the calling routine.
+func (semaphore *trackedSemaphore) hold(n int32) {
+	var holder = &Holder{Goroutine: goroutine.Id(), Permits: n, Site: callSite(), Taken: semaphore.clock.Now()}
+	semaphore.lock.Lock()
+	semaphore.holders.PushBack(holder)
+	semaphore.held += n
+	semaphore.lock.Unlock()
+}
+
+// Release n held permits, preferring those held by the calling routine and then the oldest.
+// Returns false if fewer than n permits are held.
+func (semaphore *trackedSemaphore) release(n int32) bool {
+	var id = goroutine.Id()
+	semaphore.lock.Lock()
+	if semaphore.held < n {
+		var err = &OwnershipError{Goroutine: id, Site: callSite(), Given: n, Held: semaphore.held}
+		semaphore.lock.Unlock()
+		semaphore.violation(err)
+		return false
+	}
+	semaphore.held -= n
+	var remaining = semaphore.releaseFrom(n, func(holder *Holder) bool {
+		return holder.Goroutine == id
+	})
+	semaphore.releaseFrom(remaining, func(holder *Holder) bool {
+		return true
+	})
+	semaphore.lock.Unlock()
+	return true
+}
+
+// Release up to n permits from the holders matching the filter, oldest first.  Returns the number
+// of permits left to release.
+func (semaphore *trackedSemaphore) releaseFrom(n int32, matches func(holder *Holder) bool) int32 {
+	var element = semaphore.holders.Front()
+	for element != nil && n > 0 {
+		var next = element.Next()
+		var holder = element.Value.(*Holder)
+		if matches(holder) {
+			var released = n
+			if holder.Permits < released {
+				released = holder.Permits
+			}
+			holder.Permits -= released
+			n -= released
+			if holder.Permits == 0 {
+				semaphore.holders.Remove(element)
+				delete(semaphore.reported, holder)
+			}
+		}
+		element = next
+	}
+	return n
+}
+
+func (semaphore *trackedSemaphore) Take() {
+	semaphore.TakeN(1)
+}
+
+func (semaphore *trackedSemaphore) TakeN(n int32) {
+	semaphore.countingSemaphore.TakeN(n)
+	semaphore.hold(n)
+}
+
+func (semaphore *trackedSemaphore) TryTake(timeout time.Duration) bool {
+	return semaphore.TryTakeN(1, timeout)
+}
+
+func (semaphore *trackedSemaphore) TryTakeN(n int32, timeout time.Duration) bool {
+	var ok = semaphore.countingSemaphore.TryTakeN(n, timeout)
+	if ok {
+		semaphore.hold(n)
+	}
+	return ok
+}
+
+func (semaphore *trackedSemaphore) TakeContext(ctx context.Context) error {
+	var err = semaphore.countingSemaphore.TakeContext(ctx)
+	if err == nil {
+		semaphore.hold(1)
+	}
+	return err
+}
+
+func (semaphore *trackedSemaphore) TakeNoWait() bool {
+	var ok = semaphore.countingSemaphore.TakeNoWait()
+	if ok {
+		semaphore.hold(1)
+	}
+	return ok
+}
+
+func (semaphore *trackedSemaphore) Give() bool {
+	return semaphore.GiveN(1)
+}
+
+func (semaphore *trackedSemaphore) GiveN(n int32) bool {
+	if n < 1 {
+		panic("semaphore weight less than 1")
+	}
+	if !semaphore.release(n) {
+		return false
+	}
+	return semaphore.countingSemaphore.GiveN(n)
+}
+
+func (semaphore *trackedSemaphore) Holders() []Holder {
+	semaphore.lock.Lock()
+	defer semaphore.lock.Unlock()
+	var holders = make([]Holder, 0, semaphore.holders.Len())
+	for element := semaphore.holders.Front(); element != nil; element = element.Next() {
+		holders = append(holders, *element.Value.(*Holder))
+	}
+	return holders
+}
+
+// Returns the holders whose routine has exited.  The holders are collected before the live
+// routines so a routine taking permits during the check is not mistaken for one that exited.
+func (semaphore *trackedSemaphore) abandoned() []*Holder {
+	semaphore.lock.Lock()
+	var holders = make([]*Holder, 0, semaphore.holders.Len())
+	for element := semaphore.holders.Front(); element != nil; element = element.Next() {
+		holders = append(holders, element.Value.(*Holder))
+	}
+	semaphore.lock.Unlock()
+
+	var live = goroutine.Live()
+	var abandoned []*Holder
+	for _, holder := range holders {
+		if !live[holder.Goroutine] {
+			abandoned = append(abandoned, holder)
+		}
+	}
+	return abandoned
+}
+
+func (semaphore *trackedSemaphore) Abandoned() []Holder {
+	var holders = semaphore.abandoned()
+	var abandoned []Holder
+	semaphore.lock.Lock()
+	defer semaphore.lock.Unlock()
+	for _, holder := range holders {
+		// skip holders released since they were collected
+		if holder.Permits > 0 {
+			abandoned = append(abandoned, *holder)
+		}
+	}
+	return abandoned
+}
+
+// Report the holders abandoned since the last check to the violation handler.
+func (semaphore *trackedSemaphore) checkAbandoned() {
+	var errs []error
+	var abandoned = semaphore.abandoned()
+	semaphore.lock.Lock()
+	for _, holder := range abandoned {
+		// skip holders released since they were collected or already reported
+		if holder.Permits > 0 && !semaphore.reported[holder] {
+			semaphore.reported[holder] = true
+			errs = append(errs, &AbandonedError{Holder: *holder})
+		}
+	}
+	semaphore.lock.Unlock()
+
+	// report outside the lock so the handler may take or give permits
+	for _, err := range errs {
+		semaphore.violation(err)
+	}
+}
+
+func (semaphore *trackedSemaphore) monitor(interval time.Duration) {
+	for {
+		var timer = semaphore.clock.NewTimer(interval)
+		select {
+		case <-timer.C():
+			semaphore.checkAbandoned()
+		case <-semaphore.stop:
+			timer.Stop()
+			return
+		}
+	}
+}
+
+func (semaphore *trackedSemaphore) Stop() {
+	semaphore.stopOnce.Do(func() {
+		close(semaphore.stop)
+	})
+}
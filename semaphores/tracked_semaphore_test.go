@@ -0,0 +1,162 @@
+// Copyright 2026 Jeffrey Bester <jbester@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and
+// to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of
+// the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package semaphores
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"bitbucket.org/jbester/sync/clock"
+	"bitbucket.org/jbester/sync/internal/goroutine"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TrackedHolders(t *testing.T) {
+	var semaphore = MakeTrackedCountingSemaphore(3, 3)
+	semaphore.Take()
+	assert.True(t, semaphore.TryTakeN(2, time.Millisecond))
+	var holders = semaphore.Holders()
+	assert.Len(t, holders, 2)
+	assert.Equal(t, int32(1), holders[0].Permits)
+	assert.Equal(t, int32(2), holders[1].Permits)
+	assert.Equal(t, goroutine.Id(), holders[0].Goroutine)
+	assert.True(t, strings.Contains(holders[0].Site, "tracked_semaphore_test.go"), holders[0].Site)
+
+	assert.True(t, semaphore.GiveN(2))
+	holders = semaphore.Holders()
+	assert.Len(t, holders, 1)
+	assert.Equal(t, int32(1), holders[0].Permits)
+	assert.True(t, semaphore.Give())
+	assert.Empty(t, semaphore.Holders())
+	assert.True(t, semaphore.IsFull())
+}
+
+func Test_TrackedTakeNoWait(t *testing.T) {
+	var semaphore = MakeTrackedCountingSemaphore(1, 1)
+	assert.True(t, semaphore.TakeNoWait())
+	assert.False(t, semaphore.TakeNoWait())
+	assert.Len(t, semaphore.Holders(), 1)
+	assert.True(t, semaphore.Give())
+}
+
+func Test_TrackedGiveNotTaken(t *testing.T) {
+	var semaphore = MakeTrackedCountingSemaphore(1, 2)
+	assert.Panics(t, func() {
+		semaphore.Give()
+	})
+	// the initial permit is not held so it cannot be given back
+	assert.Equal(t, int32(1), semaphore.Count())
+}
+
+func Test_TrackedDoubleGive(t *testing.T) {
+	var violations []error
+	var semaphore = MakeTrackedCountingSemaphore(2, 2, WithViolationHandler(func(err error) {
+		violations = append(violations, err)
+	}))
+	semaphore.Take()
+	assert.True(t, semaphore.Give())
+	assert.False(t, semaphore.Give())
+	assert.Len(t, violations, 1)
+	var err, ok = violations[0].(*OwnershipError)
+	assert.True(t, ok)
+	assert.Equal(t, int32(1), err.Given)
+	assert.Equal(t, int32(0), err.Held)
+	assert.True(t, strings.Contains(err.Site, "tracked_semaphore_test.go"), err.Site)
+	assert.True(t, semaphore.IsFull())
+}
+
+func Test_TrackedGiveFromOtherRoutine(t *testing.T) {
+	var semaphore = MakeTrackedCountingSemaphore(2, 2)
+	var taken = make(chan uint64)
+	go func() {
+		semaphore.Take()
+		taken <- goroutine.Id()
+	}()
+	var other = <-taken
+	semaphore.Take()
+
+	// permits held by the giving routine are released before those of other routines
+	assert.True(t, semaphore.Give())
+	var holders = semaphore.Holders()
+	assert.Len(t, holders, 1)
+	assert.Equal(t, other, holders[0].Goroutine)
+	assert.True(t, semaphore.Give())
+	assert.Empty(t, semaphore.Holders())
+}
+
+func Test_TrackedAbandoned(t *testing.T) {
+	var semaphore = MakeTrackedCountingSemaphore(2, 2)
+	var done = make(chan empty)
+	go func() {
+		semaphore.Take()
+		close(done)
+	}()
+	<-done
+	assert.NoError(t, semaphore.TakeContext(context.Background()))
+
+	// the routine holding the first permit eventually exits
+	var abandoned = semaphore.Abandoned()
+	for i := 0; i < 100 && len(abandoned) == 0; i++ {
+		<-time.After(time.Millisecond)
+		abandoned = semaphore.Abandoned()
+	}
+	assert.Len(t, abandoned, 1)
+	assert.NotEqual(t, goroutine.Id(), abandoned[0].Goroutine)
+	assert.Len(t, semaphore.Holders(), 2)
+}
+
+func Test_TrackedAbandonCheck(t *testing.T) {
+	var fake = clock.MakeFakeClock(time.Now())
+	var violations = make(chan error, 4)
+	var semaphore = MakeTrackedCountingSemaphore(2, 2, WithClock(fake), WithAbandonCheck(time.Second),
+		WithViolationHandler(func(err error) {
+			violations <- err
+		}))
+	defer semaphore.Stop()
+	var done = make(chan empty)
+	go func() {
+		semaphore.Take()
+		close(done)
+	}()
+	<-done
+	semaphore.Take()
+
+	// the routine holding the first permit eventually exits and is reported by a check
+	var reported error
+	for i := 0; i < 100 && reported == nil; i++ {
+		fake.WaitForTimers(1)
+		fake.Advance(time.Second)
+		select {
+		case reported = <-violations:
+		case <-time.After(time.Millisecond):
+		}
+	}
+	var err, ok = reported.(*AbandonedError)
+	assert.True(t, ok)
+	assert.NotEqual(t, goroutine.Id(), err.Holder.Goroutine)
+	assert.Equal(t, int32(1), err.Holder.Permits)
+	assert.True(t, strings.Contains(err.Holder.Site, "tracked_semaphore_test.go"), err.Holder.Site)
+
+	// an abandoned holder is reported once
+	for i := 0; i < 3; i++ {
+		fake.WaitForTimers(1)
+		fake.Advance(time.Second)
+	}
+	fake.WaitForTimers(1)
+	assert.Len(t, violations, 0)
+}
@@ -0,0 +1,191 @@
+// Copyright 2026 Jeffrey Bester <jbester@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and
+// to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of
+// the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package startgroup
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"bitbucket.org/jbester/sync/clock"
+	"bitbucket.org/jbester/sync/watchdog"
+)
+
+// Returned by a barrier wait when another party timed out or was cancelled, or the barrier was
+// reset, before all parties arrived.
+var ErrBrokenBarrier = errors.New("barrier broken")
+
+// Returned by a timed barrier wait to the party whose timeout expired.
+var ErrBarrierTimeout = errors.New("barrier wait timed out")
+
+// A Barrier releases a fixed number of parties together once all of them are waiting, then resets
+// for the next round.  Each round is a generation; a party that times out or is cancelled breaks
+// the current generation and every other party waiting in it returns ErrBrokenBarrier instead of
+// hanging.  A broken barrier stays broken until it is Reset.
+type Barrier interface {
+	// Wait until all parties have arrived.
+	Await() error
+
+	// Wait until all parties have arrived for up to a timeout.  If the timeout expires the
+	// barrier is broken and ErrBarrierTimeout is returned.
+	TimedAwait(timeout time.Duration) error
+
+	// Wait until all parties have arrived or the context is done.  If the context finishes
+	// first the barrier is broken and the context's error is returned.
+	AwaitContext(ctx context.Context) error
+
+	// Break the current generation, releasing any waiting parties with ErrBrokenBarrier, and
+	// start a new generation.
+	Reset()
+
+	// Parties returns the number of parties required to trip the barrier.
+	Parties() int
+
+	// Waiting returns the number of parties currently waiting.
+	Waiting() int
+
+	// IsBroken returns true if the current generation is broken.
+	IsBroken() bool
+}
+
+type generation struct {
+	broken bool
+}
+
+type barrier struct {
+	lock       *sync.Mutex
+	release    StartGroup
+	parties    int
+	arrived    int
+	generation *generation
+	clock      clock.Clock
+	watchdog   watchdog.Watchdog
+}
+
+// Create a Barrier for the given number of parties.
+func MakeBarrier(parties int, opts ...Option) Barrier {
+	if parties < 1 {
+		panic("barrier created with fewer than one party")
+	}
+	var options = makeOptions(opts)
+	return &barrier{
+		lock:       &sync.Mutex{},
+		release:    MakeStartGroup(),
+		parties:    parties,
+		generation: &generation{},
+		clock:      options.clock,
+		watchdog:   options.watchdog,
+	}
+}
+
+// Start a new generation, releasing the parties of the current one.  Must be called with the
+// lock held.
+func (b *barrier) nextGeneration() {
+	b.arrived = 0
+	b.generation = &generation{}
+	b.release.Release()
+}
+
+// Break the current generation, releasing its parties.  Must be called with the lock held.
+func (b *barrier) breakGeneration() {
+	b.generation.broken = true
+	b.arrived = 0
+	b.release.Release()
+}
+
+func (b *barrier) await(expired <-chan time.Time, done <-chan struct{}) error {
+	b.lock.Lock()
+	var gen = b.generation
+	if gen.broken {
+		b.lock.Unlock()
+		return ErrBrokenBarrier
+	}
+	b.arrived++
+	if b.arrived == b.parties {
+		b.nextGeneration()
+		b.lock.Unlock()
+		return nil
+	}
+	var released = b.release.Done()
+	b.lock.Unlock()
+
+	var end = b.watchdog.Begin("barrier")
+	var err error
+	select {
+	case <-released:
+	case <-expired:
+		err = ErrBarrierTimeout
+	case <-done:
+		err = context.Canceled
+	}
+	end()
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if gen.broken {
+		return ErrBrokenBarrier
+	}
+	if gen != b.generation {
+		// the barrier tripped before the wait gave up
+		return nil
+	}
+	b.breakGeneration()
+	return err
+}
+
+func (b *barrier) Await() error {
+	return b.await(nil, nil)
+}
+
+func (b *barrier) TimedAwait(timeout time.Duration) error {
+	var timer = b.clock.NewTimer(timeout)
+	defer timer.Stop()
+	return b.await(timer.C(), nil)
+}
+
+func (b *barrier) AwaitContext(ctx context.Context) error {
+	var err = b.await(nil, ctx.Done())
+	if err == context.Canceled {
+		err = ctx.Err()
+	}
+	return err
+}
+
+func (b *barrier) Reset() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.arrived > 0 {
+		b.breakGeneration()
+	}
+	b.nextGeneration()
+}
+
+func (b *barrier) Parties() int {
+	return b.parties
+}
+
+func (b *barrier) Waiting() int {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.arrived
+}
+
+func (b *barrier) IsBroken() bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.generation.broken
+}
@@ -0,0 +1,155 @@
+// Copyright 2026 Jeffrey Bester <jbester@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and
+// to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of
+// the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package startgroup
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"bitbucket.org/jbester/sync/clock"
+	"bitbucket.org/jbester/sync/watchdog"
+	"github.com/stretchr/testify/assert"
+)
+
+// wait until the given number of parties are waiting on the barrier
+func waitForParties(b Barrier, count int) {
+	for b.Waiting() != count {
+		<-time.After(time.Millisecond)
+	}
+}
+
+func Test_BarrierReleasesAllParties(t *testing.T) {
+	var b = MakeBarrier(3)
+	var errs = make(chan error, 3)
+	for i := 0; i < 3; i++ {
+		go func() {
+			errs <- b.Await()
+		}()
+	}
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, <-errs)
+	}
+	assert.Equal(t, 0, b.Waiting())
+	assert.False(t, b.IsBroken())
+}
+
+func Test_BarrierReuse(t *testing.T) {
+	const parties = 4
+	const rounds = 50
+	var b = MakeBarrier(parties)
+	var counter int32 = 0
+	var done = &sync.WaitGroup{}
+	for i := 0; i < parties; i++ {
+		done.Add(1)
+		go func() {
+			defer done.Done()
+			for round := 0; round < rounds; round++ {
+				atomic.AddInt32(&counter, 1)
+				assert.NoError(t, b.Await())
+				// every party has arrived for this round
+				assert.GreaterOrEqual(t, atomic.LoadInt32(&counter), int32(parties*(round+1)))
+				assert.NoError(t, b.Await())
+			}
+		}()
+	}
+	done.Wait()
+	assert.Equal(t, int32(parties*rounds), counter)
+}
+
+func Test_BarrierTimeoutBreaks(t *testing.T) {
+	var b = MakeBarrier(3)
+	var other = make(chan error)
+	go func() {
+		other <- b.Await()
+	}()
+	waitForParties(b, 1)
+	assert.Equal(t, ErrBarrierTimeout, b.TimedAwait(time.Millisecond))
+	assert.Equal(t, ErrBrokenBarrier, <-other)
+	assert.True(t, b.IsBroken())
+	// the barrier stays broken
+	assert.Equal(t, ErrBrokenBarrier, b.Await())
+}
+
+func Test_BarrierContextBreaks(t *testing.T) {
+	var b = MakeBarrier(2)
+	var ctx, cancel = context.WithCancel(context.Background())
+	var result = make(chan error)
+	go func() {
+		result <- b.AwaitContext(ctx)
+	}()
+	waitForParties(b, 1)
+	cancel()
+	assert.Equal(t, context.Canceled, <-result)
+	assert.True(t, b.IsBroken())
+}
+
+func Test_BarrierReset(t *testing.T) {
+	var b = MakeBarrier(2)
+	var other = make(chan error)
+	go func() {
+		other <- b.Await()
+	}()
+	waitForParties(b, 1)
+	b.Reset()
+	assert.Equal(t, ErrBrokenBarrier, <-other)
+	assert.False(t, b.IsBroken())
+
+	go func() {
+		other <- b.Await()
+	}()
+	assert.NoError(t, b.Await())
+	assert.NoError(t, <-other)
+}
+
+func Test_BarrierInvalid(t *testing.T) {
+	assert.Panics(t, func() {
+		MakeBarrier(0)
+	})
+}
+
+func Test_BarrierTimeoutFakeClock(t *testing.T) {
+	var fake = clock.MakeFakeClock(time.Now())
+	var b = MakeBarrier(2, WithClock(fake))
+	var result = make(chan error)
+	go func() {
+		result <- b.TimedAwait(time.Hour)
+	}()
+	fake.WaitForTimers(1)
+	fake.Advance(time.Hour)
+	assert.Equal(t, ErrBarrierTimeout, <-result)
+}
+
+// Verify a blocked wait is recorded by the watchdog
+func Test_BarrierWatchdog(t *testing.T) {
+	var dog = watchdog.MakeWatchdog(time.Hour)
+	defer dog.Stop()
+	var b = MakeBarrier(2, WithWatchdog(dog))
+	var done = make(chan struct{})
+	go func() {
+		b.Await()
+		close(done)
+	}()
+	for len(dog.Waits()) == 0 {
+		<-time.After(time.Millisecond)
+	}
+	assert.Equal(t, "barrier", dog.Waits()[0].Kind)
+	b.Await()
+	<-done
+	assert.Empty(t, dog.Waits())
+}
@@ -0,0 +1,143 @@
+// Copyright 2026 Jeffrey Bester <jbester@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and
+// to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of
+// the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package startgroup
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"bitbucket.org/jbester/sync/clock"
+	"bitbucket.org/jbester/sync/watchdog"
+)
+
+// A CountDownLatch releases waiting goroutines once a count, set when the latch is created,
+// has been counted down to zero.  Unlike sync.WaitGroup the remaining count may be queried and
+// waits may time out or be cancelled.  Once released the latch stays released.
+type CountDownLatch interface {
+	// Decrement the count, releasing all waiting goroutines when it reaches zero.  Counting
+	// down a released latch has no effect.
+	CountDown()
+
+	// Count returns the remaining count.
+	Count() int32
+
+	// Wait for the count to reach zero
+	Wait()
+
+	// Wait for the count to reach zero for up to a timeout
+	TimedWait(timeout time.Duration) bool
+
+	// Wait for the count to reach zero until the context is done.  Returns the context's
+	// error if the context finished first.
+	WaitContext(ctx context.Context) error
+
+	// Done returns a channel that is closed when the count reaches zero.
+	Done() <-chan struct{}
+}
+
+type countDownLatch struct {
+	count    int32
+	released StartGroup
+	clock    clock.Clock
+	watchdog watchdog.Watchdog
+}
+
+// A channel that is always closed, returned by Done once a latch is released.
+var closedChannel = make(chan struct{})
+
+func init() {
+	close(closedChannel)
+}
+
+// Create a CountDownLatch with the given count.  A latch created with a count of zero or less
+// is already released.
+func MakeCountDownLatch(count int32, opts ...Option) CountDownLatch {
+	if count < 0 {
+		count = 0
+	}
+	var options = makeOptions(opts)
+	return &countDownLatch{
+		count:    count,
+		released: MakeStartGroup(),
+		clock:    options.clock,
+		watchdog: options.watchdog,
+	}
+}
+
+func (latch *countDownLatch) CountDown() {
+	for {
+		var count = latch.Count()
+		if count == 0 {
+			return
+		}
+		if atomic.CompareAndSwapInt32(&latch.count, count, count-1) {
+			if count == 1 {
+				latch.released.Release()
+			}
+			return
+		}
+	}
+}
+
+func (latch *countDownLatch) Count() int32 {
+	return atomic.LoadInt32(&latch.count)
+}
+
+func (latch *countDownLatch) Done() <-chan struct{} {
+	// take the release channel before checking the count so the final count down closes it
+	var done = latch.released.Done()
+	if latch.Count() == 0 {
+		return closedChannel
+	}
+	return done
+}
+
+func (latch *countDownLatch) Wait() {
+	if latch.Count() == 0 {
+		return
+	}
+	defer latch.watchdog.Begin("count down latch")()
+	<-latch.Done()
+}
+
+func (latch *countDownLatch) TimedWait(timeout time.Duration) bool {
+	if latch.Count() == 0 {
+		return true
+	}
+	defer latch.watchdog.Begin("count down latch")()
+	var timer = latch.clock.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-latch.Done():
+		return true
+	case <-timer.C():
+		return false
+	}
+}
+
+func (latch *countDownLatch) WaitContext(ctx context.Context) error {
+	if latch.Count() == 0 {
+		return nil
+	}
+	defer latch.watchdog.Begin("count down latch")()
+	select {
+	case <-latch.Done():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
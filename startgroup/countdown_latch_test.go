@@ -0,0 +1,125 @@
+// Copyright 2026 Jeffrey Bester <jbester@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and
+// to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of
+// the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package startgroup
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"bitbucket.org/jbester/sync/clock"
+	"bitbucket.org/jbester/sync/watchdog"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_LatchCountDown(t *testing.T) {
+	var latch = MakeCountDownLatch(2)
+	assert.Equal(t, int32(2), latch.Count())
+	latch.CountDown()
+	assert.Equal(t, int32(1), latch.Count())
+	assert.False(t, latch.TimedWait(time.Millisecond))
+	latch.CountDown()
+	assert.Equal(t, int32(0), latch.Count())
+	assert.True(t, latch.TimedWait(time.Millisecond))
+	// counting down a released latch has no effect
+	latch.CountDown()
+	assert.Equal(t, int32(0), latch.Count())
+}
+
+func Test_LatchZero(t *testing.T) {
+	var latch = MakeCountDownLatch(0)
+	latch.Wait()
+	<-latch.Done()
+}
+
+func Test_LatchReleasesWaiters(t *testing.T) {
+	var latch = MakeCountDownLatch(3)
+	var released int32 = 0
+	var started = &sync.WaitGroup{}
+	var done = &sync.WaitGroup{}
+	for i := 0; i < 5; i++ {
+		started.Add(1)
+		done.Add(1)
+		go func() {
+			defer done.Done()
+			started.Done()
+			latch.Wait()
+			atomic.AddInt32(&released, 1)
+		}()
+	}
+	started.Wait()
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, int32(0), atomic.LoadInt32(&released))
+		go latch.CountDown()
+	}
+	done.Wait()
+	assert.Equal(t, int32(5), released)
+}
+
+func Test_LatchWaitContext(t *testing.T) {
+	var latch = MakeCountDownLatch(1)
+	var ctx, cancel = context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, latch.WaitContext(ctx))
+	latch.CountDown()
+	assert.NoError(t, latch.WaitContext(ctx))
+}
+
+func Test_LatchDone(t *testing.T) {
+	var latch = MakeCountDownLatch(1)
+	var done = latch.Done()
+	select {
+	case <-done:
+		assert.Fail(t, "done closed before count reached zero")
+	default:
+	}
+	latch.CountDown()
+	<-done
+	<-latch.Done()
+}
+
+func Test_LatchTimedWaitFakeClock(t *testing.T) {
+	var fake = clock.MakeFakeClock(time.Now())
+	var latch = MakeCountDownLatch(1, WithClock(fake))
+	var result = make(chan bool)
+	go func() {
+		result <- latch.TimedWait(time.Hour)
+	}()
+	fake.WaitForTimers(1)
+	fake.Advance(time.Hour)
+	assert.False(t, <-result)
+}
+
+// Verify a blocked wait is recorded by the watchdog
+func Test_CountDownLatchWatchdog(t *testing.T) {
+	var dog = watchdog.MakeWatchdog(time.Hour)
+	defer dog.Stop()
+	var latch = MakeCountDownLatch(1, WithWatchdog(dog))
+	var done = make(chan struct{})
+	go func() {
+		latch.Wait()
+		close(done)
+	}()
+	for len(dog.Waits()) == 0 {
+		<-time.After(time.Millisecond)
+	}
+	assert.Equal(t, "count down latch", dog.Waits()[0].Kind)
+	latch.CountDown()
+	<-done
+	assert.Empty(t, dog.Waits())
+}
@@ -0,0 +1,216 @@
+// Copyright 2026 Jeffrey Bester <jbester@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and
+// to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of
+// the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package startgroup
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"bitbucket.org/jbester/sync/clock"
+	"bitbucket.org/jbester/sync/watchdog"
+)
+
+// A Phaser is a reusable barrier whose number of parties may change between and during phases.
+// Parties register with the phaser and each phase advances when every registered party has
+// arrived.  Parties may arrive without waiting, arrive and wait for the phase to advance, or
+// arrive and deregister, leaving the phaser.
+type Phaser interface {
+	// Register a new party.  Returns the phase the party joined.
+	Register() int
+
+	// Register the given number of new parties.  Returns the phase the parties joined.
+	BulkRegister(parties int) int
+
+	// Arrive at the current phase without waiting for the other parties.  Returns the
+	// arrival phase.  Panics if every registered party has already arrived.
+	Arrive() int
+
+	// Arrive at the current phase and leave the phaser without waiting for the other parties.
+	// Returns the arrival phase.  Panics if every registered party has already arrived.
+	ArriveAndDeregister() int
+
+	// Arrive at the current phase and wait for the other parties.  Returns the phase after
+	// the advance.  Panics if every registered party has already arrived.
+	ArriveAndAwaitAdvance() int
+
+	// Wait for the phaser to advance from the given phase.  Returns immediately if the current
+	// phase differs from the given phase.  Returns the current phase.
+	AwaitAdvance(phase int) int
+
+	// Wait for the phaser to advance from the given phase for up to a timeout.  Returns the
+	// current phase and true if the phaser advanced.
+	TimedAwaitAdvance(phase int, timeout time.Duration) (int, bool)
+
+	// Wait for the phaser to advance from the given phase until the context is done.  Returns
+	// the current phase and the context's error if the context finished first.
+	AwaitAdvanceContext(ctx context.Context, phase int) (int, error)
+
+	// Phase returns the current phase number.
+	Phase() int
+
+	// RegisteredParties returns the number of registered parties.
+	RegisteredParties() int
+
+	// ArrivedParties returns the number of parties that have arrived at the current phase.
+	ArrivedParties() int
+
+	// UnarrivedParties returns the number of registered parties yet to arrive at the current
+	// phase.
+	UnarrivedParties() int
+}
+
+type phaser struct {
+	lock       *sync.Mutex
+	release    StartGroup
+	phase      int
+	registered int
+	arrived    int
+	clock      clock.Clock
+	watchdog   watchdog.Watchdog
+}
+
+// Create a Phaser with the given number of initially registered parties.
+func MakePhaser(parties int, opts ...Option) Phaser {
+	if parties < 0 {
+		panic("phaser created with negative parties")
+	}
+	var options = makeOptions(opts)
+	return &phaser{
+		lock:       &sync.Mutex{},
+		release:    MakeStartGroup(),
+		registered: parties,
+		clock:      options.clock,
+		watchdog:   options.watchdog,
+	}
+}
+
+// Advance to the next phase if every registered party has arrived.  Must be called with the
+// lock held.
+func (p *phaser) tryAdvance() {
+	if p.arrived == p.registered {
+		p.arrived = 0
+		p.phase++
+		p.release.Release()
+	}
+}
+
+// Arrive at the current phase, deregistering if requested.  Returns the arrival phase.
+func (p *phaser) arrive(deregister bool) int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.arrived >= p.registered {
+		panic("phaser arrival with no unarrived parties")
+	}
+	var phase = p.phase
+	if deregister {
+		p.registered--
+	} else {
+		p.arrived++
+	}
+	p.tryAdvance()
+	return phase
+}
+
+// Wait for the phaser to advance from the given phase.  Returns the current phase and false if
+// the expired channel fires or the done channel is closed first.
+func (p *phaser) awaitAdvance(phase int, expired <-chan time.Time, done <-chan struct{}) (int, bool) {
+	p.lock.Lock()
+	var current = p.phase
+	var released = p.release.Done()
+	p.lock.Unlock()
+	if current != phase {
+		return current, true
+	}
+
+	defer p.watchdog.Begin("phaser")()
+	select {
+	case <-released:
+	case <-expired:
+	case <-done:
+	}
+	current = p.Phase()
+	return current, current != phase
+}
+
+func (p *phaser) Register() int {
+	return p.BulkRegister(1)
+}
+
+func (p *phaser) BulkRegister(parties int) int {
+	if parties < 0 {
+		panic("phaser registration of negative parties")
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.registered += parties
+	return p.phase
+}
+
+func (p *phaser) Arrive() int {
+	return p.arrive(false)
+}
+
+func (p *phaser) ArriveAndDeregister() int {
+	return p.arrive(true)
+}
+
+func (p *phaser) ArriveAndAwaitAdvance() int {
+	return p.AwaitAdvance(p.arrive(false))
+}
+
+func (p *phaser) AwaitAdvance(phase int) int {
+	var current, _ = p.awaitAdvance(phase, nil, nil)
+	return current
+}
+
+func (p *phaser) TimedAwaitAdvance(phase int, timeout time.Duration) (int, bool) {
+	var timer = p.clock.NewTimer(timeout)
+	defer timer.Stop()
+	return p.awaitAdvance(phase, timer.C(), nil)
+}
+
+func (p *phaser) AwaitAdvanceContext(ctx context.Context, phase int) (int, error) {
+	var current, ok = p.awaitAdvance(phase, nil, ctx.Done())
+	if !ok {
+		return current, ctx.Err()
+	}
+	return current, nil
+}
+
+func (p *phaser) Phase() int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.phase
+}
+
+func (p *phaser) RegisteredParties() int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.registered
+}
+
+func (p *phaser) ArrivedParties() int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.arrived
+}
+
+func (p *phaser) UnarrivedParties() int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.registered - p.arrived
+}
@@ -0,0 +1,144 @@
+// Copyright 2026 Jeffrey Bester <jbester@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and
+// to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of
+// the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package startgroup
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"bitbucket.org/jbester/sync/clock"
+	"bitbucket.org/jbester/sync/watchdog"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_PhaserAdvance(t *testing.T) {
+	var p = MakePhaser(2)
+	assert.Equal(t, 0, p.Arrive())
+	assert.Equal(t, 0, p.Phase())
+	assert.Equal(t, 1, p.ArrivedParties())
+	assert.Equal(t, 1, p.UnarrivedParties())
+	assert.Equal(t, 0, p.Arrive())
+	assert.Equal(t, 1, p.Phase())
+	assert.Equal(t, 0, p.ArrivedParties())
+}
+
+func Test_PhaserArriveAndAwaitAdvance(t *testing.T) {
+	const parties = 3
+	const phases = 20
+	var p = MakePhaser(parties)
+	var arrivals int32 = 0
+	var done = &sync.WaitGroup{}
+	for i := 0; i < parties; i++ {
+		done.Add(1)
+		go func() {
+			defer done.Done()
+			for phase := 0; phase < phases; phase++ {
+				atomic.AddInt32(&arrivals, 1)
+				assert.Equal(t, phase+1, p.ArriveAndAwaitAdvance())
+				// every party arrived before the phase advanced
+				assert.GreaterOrEqual(t, atomic.LoadInt32(&arrivals), int32(parties*(phase+1)))
+			}
+		}()
+	}
+	done.Wait()
+	assert.Equal(t, phases, p.Phase())
+}
+
+func Test_PhaserDynamicRegistration(t *testing.T) {
+	var p = MakePhaser(1)
+	assert.Equal(t, 0, p.Register())
+	assert.Equal(t, 2, p.RegisteredParties())
+	var result = make(chan int)
+	go func() {
+		result <- p.ArriveAndAwaitAdvance()
+	}()
+	// the second party leaves, completing the phase
+	assert.Equal(t, 0, p.ArriveAndDeregister())
+	assert.Equal(t, 1, <-result)
+	assert.Equal(t, 1, p.RegisteredParties())
+
+	// a worker joins the next phase
+	assert.Equal(t, 1, p.BulkRegister(2))
+	p.Arrive()
+	p.Arrive()
+	assert.Equal(t, 1, p.Phase())
+	p.Arrive()
+	assert.Equal(t, 2, p.Phase())
+}
+
+func Test_PhaserArriveUnregistered(t *testing.T) {
+	var p = MakePhaser(0)
+	assert.Panics(t, func() {
+		p.Arrive()
+	})
+}
+
+func Test_PhaserAwaitAdvance(t *testing.T) {
+	var p = MakePhaser(1)
+	// waiting on a past phase returns immediately
+	p.Arrive()
+	assert.Equal(t, 1, p.AwaitAdvance(0))
+
+	var phase, ok = p.TimedAwaitAdvance(1, time.Millisecond)
+	assert.False(t, ok)
+	assert.Equal(t, 1, phase)
+
+	var ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	phase, err := p.AwaitAdvanceContext(ctx, 1)
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 1, phase)
+
+	go p.Arrive()
+	phase, ok = p.TimedAwaitAdvance(1, time.Second)
+	assert.True(t, ok)
+	assert.Equal(t, 2, phase)
+}
+
+func Test_PhaserTimedAwaitFakeClock(t *testing.T) {
+	var fake = clock.MakeFakeClock(time.Now())
+	var p = MakePhaser(1, WithClock(fake))
+	var result = make(chan bool)
+	go func() {
+		var _, ok = p.TimedAwaitAdvance(0, time.Hour)
+		result <- ok
+	}()
+	fake.WaitForTimers(1)
+	fake.Advance(time.Hour)
+	assert.False(t, <-result)
+}
+
+// Verify a blocked wait is recorded by the watchdog
+func Test_PhaserWatchdog(t *testing.T) {
+	var dog = watchdog.MakeWatchdog(time.Hour)
+	defer dog.Stop()
+	var p = MakePhaser(2, WithWatchdog(dog))
+	var done = make(chan struct{})
+	go func() {
+		p.ArriveAndAwaitAdvance()
+		close(done)
+	}()
+	for len(dog.Waits()) == 0 {
+		<-time.After(time.Millisecond)
+	}
+	assert.Equal(t, "phaser", dog.Waits()[0].Kind)
+	p.Arrive()
+	<-done
+	assert.Empty(t, dog.Waits())
+}
@@ -13,16 +13,22 @@
 // OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
 // OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
 
-// Package startgroup provides a single synchronization primitive the StartGroup.
+// Package startgroup provides the StartGroup synchronization primitive and the CountDownLatch,
+// Barrier and Phaser built on it.  A TypedStartGroup hands a value to every released goroutine.
 //
+// Waiting routines block on a channel that is closed and replaced on each release, so timed and
+// cancelled waits leave nothing behind to be cleaned up by a later release.
 package startgroup
 
 import (
+	"context"
 	"sync"
 	"time"
-)
 
-type empty struct{}
+	"bitbucket.org/jbester/sync/clock"
+	"bitbucket.org/jbester/sync/metrics"
+	"bitbucket.org/jbester/sync/watchdog"
+)
 
 // A StartGroup provides a mechanism for a collection of goroutines to wait for a release event.
 // When released, all blocked routines simultaneously.
@@ -38,57 +44,108 @@ type StartGroup interface {
 
 	// Wait for a release event for up to a timeout
 	TimedWait(timeout time.Duration) bool
+
+	// Wait for a release event until the context is done.  Returns the context's error if
+	// the context finished first.
+	WaitContext(ctx context.Context) error
+
+	// Done returns a channel that is closed by the next release event.  Each release
+	// closes the channel returned before it; call Done again to wait for a later release.
+	Done() <-chan struct{}
+
+	// Returns a snapshot of the group's statistics.  Waits that are released are counted as
+	// acquisitions.
+	Stats() metrics.Stats
 }
 
 type startGroup struct {
-	lock       *sync.RWMutex
-	notifyList *sync.WaitGroup
+	lock     *sync.RWMutex
+	release  chan struct{}
+	clock    clock.Clock
+	stats    *metrics.Collector
+	watchdog watchdog.Watchdog
 }
 
 //  Create a StartGroup.
-func MakeStartGroup() StartGroup {
-	var wg = &sync.WaitGroup{}
-	wg.Add(1)
-	return &startGroup{lock: &sync.RWMutex{}, notifyList: wg}
+func MakeStartGroup(opts ...Option) StartGroup {
+	var options = makeOptions(opts)
+	return &startGroup{
+		lock:     &sync.RWMutex{},
+		release:  make(chan struct{}),
+		clock:    options.clock,
+		stats:    metrics.MakeCollector(options.clock),
+		watchdog: options.watchdog,
+	}
+}
+
+//  Create a StartGroup registered under the name so its statistics are exported.
+func MakeNamedStartGroup(name string, opts ...Option) StartGroup {
+	var group = MakeStartGroup(opts...)
+	makeOptions(opts).registry.Register(name, group)
+	return group
 }
 
 func (group *startGroup) Release() {
-	// create a wg waitgroup
-	var wg = &sync.WaitGroup{}
-	wg.Add(1)
+	// create a new release channel
+	var ch = make(chan struct{})
 
 	// mutex is to prevent multiple goroutines from trying to release simultaneously
 	group.lock.Lock()
-	// swap the wg waitgroup in for the old one
-	var old = group.notifyList
-	group.notifyList = wg
+	// swap the new channel in for the old one
+	var old = group.release
+	group.release = ch
 	group.lock.Unlock()
 
 	// release the old one
-	old.Done()
+	close(old)
 }
 
-func (group *startGroup) Wait() {
+func (group *startGroup) Done() <-chan struct{} {
 	group.lock.RLock()
-	var waitList = group.notifyList
-	group.lock.RUnlock()
-	waitList.Wait()
+	defer group.lock.RUnlock()
+	return group.release
+}
+
+func (group *startGroup) Wait() {
+	// join the release before counting as waiting so a routine counted as waiting is
+	// released by the next release
+	var release = group.Done()
+	var wait = group.stats.BeginWait()
+	defer group.watchdog.Begin("start group")()
+	<-release
+	wait.End(metrics.Acquired)
 }
 
 func (group *startGroup) TimedWait(timeout time.Duration) bool {
-	group.lock.RLock()
-	var waitList = group.notifyList
-	group.lock.RUnlock()
-	var ch = make(chan empty)
-	defer close(ch)
-	go func() {
-		waitList.Wait()
-		ch <- empty{}
-	}()
+	var release = group.Done()
+	var wait = group.stats.BeginWait()
+	defer group.watchdog.Begin("start group")()
+	var timer = group.clock.NewTimer(timeout)
+	defer timer.Stop()
 	select {
-	case <-ch:
+	case <-release:
+		wait.End(metrics.Acquired)
 		return true
-	case <-time.After(timeout):
+	case <-timer.C():
+		wait.End(metrics.TimedOut)
 		return false
 	}
 }
+
+func (group *startGroup) WaitContext(ctx context.Context) error {
+	var release = group.Done()
+	var wait = group.stats.BeginWait()
+	defer group.watchdog.Begin("start group")()
+	select {
+	case <-release:
+		wait.End(metrics.Acquired)
+		return nil
+	case <-ctx.Done():
+		wait.End(metrics.Cancelled)
+		return ctx.Err()
+	}
+}
+
+func (group *startGroup) Stats() metrics.Stats {
+	return group.stats.Stats()
+}
@@ -16,11 +16,16 @@
 package startgroup
 
 import (
+	"context"
+	"runtime"
 	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"bitbucket.org/jbester/sync/clock"
+	"bitbucket.org/jbester/sync/metrics"
+	"bitbucket.org/jbester/sync/watchdog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 )
@@ -40,8 +45,8 @@ type callback func()
 
 //  Spawn a routine to wait on the startgroup
 func (suite *StartGroupTestSuite) asyncWait(onWaitComplete callback) {
+	suite.waitGroup.Add(1)
 	go func() {
-		suite.waitGroup.Add(1)
 		defer suite.waitGroup.Done()
 		suite.startGroup.Wait()
 		onWaitComplete()
@@ -99,13 +104,199 @@ func (suite *StartGroupTestSuite) Test_Reuse() {
 	assert.Equal(suite.T(), int32(10), done2)
 }
 
+// Verify the done channel is closed by a release and usable in a select
+func (suite *StartGroupTestSuite) Test_Done() {
+	var done = suite.startGroup.Done()
+	select {
+	case <-done:
+		assert.Fail(suite.T(), "done closed before release")
+	default:
+	}
+	suite.startGroup.Release()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		assert.Fail(suite.T(), "done not closed by release")
+	}
+	// a later done channel waits for the next release
+	select {
+	case <-suite.startGroup.Done():
+		assert.Fail(suite.T(), "done closed before second release")
+	default:
+	}
+}
+
+// wait until the given number of routines are blocked on the start group
+func waitForWaiters(group StartGroup, count int64) {
+	for group.Stats().Waiters != count {
+		<-time.After(time.Millisecond)
+	}
+}
+
+// Verify a timed wait times out without a release
+func (suite *StartGroupTestSuite) Test_TimedWait() {
+	assert.False(suite.T(), suite.startGroup.TimedWait(time.Millisecond))
+	var result = make(chan bool)
+	go func() {
+		result <- suite.startGroup.TimedWait(time.Minute)
+	}()
+	// release only once the routine is waiting; a release reaches waiting routines only
+	waitForWaiters(suite.startGroup, 1)
+	suite.startGroup.Release()
+	assert.True(suite.T(), <-result)
+}
+
+// Verify a context wait returns when released or when the context is cancelled
+func (suite *StartGroupTestSuite) Test_WaitContext() {
+	var ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(suite.T(), context.Canceled, suite.startGroup.WaitContext(ctx))
+
+	var result = make(chan error)
+	go func() {
+		result <- suite.startGroup.WaitContext(context.Background())
+	}()
+	waitForWaiters(suite.startGroup, 1)
+	suite.startGroup.Release()
+	assert.NoError(suite.T(), <-result)
+}
+
 func TestStartGroupTestSuite(t *testing.T) {
 	suite.Run(t, new(StartGroupTestSuite))
 }
 
+// wait for the number of goroutines to settle back to at most the expected count
+func settleGoroutines(expected int) int {
+	var count = runtime.NumGoroutine()
+	for i := 0; i < 100 && count > expected; i++ {
+		<-time.After(time.Millisecond * 10)
+		count = runtime.NumGoroutine()
+	}
+	return count
+}
+
+// Verify timed-out waits leave no goroutines behind and a later release does not panic
+func Test_TimedWaitNoLeak(t *testing.T) {
+	var group = MakeStartGroup()
+	var before = runtime.NumGoroutine()
+	var done = &sync.WaitGroup{}
+	for i := 0; i < 2000; i++ {
+		done.Add(1)
+		go func() {
+			defer done.Done()
+			group.TimedWait(time.Microsecond)
+		}()
+	}
+	done.Wait()
+	assert.NotPanics(t, group.Release)
+	assert.LessOrEqual(t, settleGoroutines(before), before)
+}
+
+// Verify cancelled waits leave no goroutines behind
+func Test_WaitContextNoLeak(t *testing.T) {
+	var group = MakeStartGroup()
+	var before = runtime.NumGoroutine()
+	var done = &sync.WaitGroup{}
+	for i := 0; i < 2000; i++ {
+		done.Add(1)
+		go func() {
+			defer done.Done()
+			var ctx, cancel = context.WithTimeout(context.Background(), time.Microsecond)
+			defer cancel()
+			group.WaitContext(ctx)
+		}()
+	}
+	done.Wait()
+	assert.NotPanics(t, group.Release)
+	assert.LessOrEqual(t, settleGoroutines(before), before)
+}
+
+// Verify repeated timed waits with interleaved releases do not panic
+func Test_TimedWaitInterleavedRelease(t *testing.T) {
+	var group = MakeStartGroup()
+	var done = &sync.WaitGroup{}
+	for i := 0; i < 1000; i++ {
+		done.Add(1)
+		go func() {
+			defer done.Done()
+			group.TimedWait(time.Microsecond * 10)
+		}()
+		if i%10 == 0 {
+			group.Release()
+		}
+	}
+	done.Wait()
+	assert.NotPanics(t, group.Release)
+}
+
 func Benchmark_Release(b *testing.B) {
 	var sg = MakeStartGroup()
 	for n := 0; n < b.N; n++ {
 		sg.Release()
 	}
 }
+
+// Verify a timed wait expires by the injected clock
+func Test_TimedWaitFakeClock(t *testing.T) {
+	var fake = clock.MakeFakeClock(time.Now())
+	var group = MakeStartGroup(WithClock(fake))
+	var result = make(chan bool)
+	go func() {
+		result <- group.TimedWait(time.Hour)
+	}()
+	fake.WaitForTimers(1)
+	fake.Advance(time.Hour)
+	assert.False(t, <-result)
+}
+
+// Verify waits on a start group are counted
+func Test_StartGroupStats(t *testing.T) {
+	var fake = clock.MakeFakeClock(time.Now())
+	var group = MakeStartGroup(WithClock(fake))
+	var result = make(chan bool, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			result <- group.TimedWait(time.Minute)
+		}()
+	}
+	fake.WaitForTimers(2)
+	assert.Equal(t, int64(2), group.Stats().Waiters)
+	group.Release()
+	<-result
+	<-result
+	assert.False(t, group.TimedWait(0))
+
+	var stats = group.Stats()
+	assert.Equal(t, uint64(2), stats.Acquisitions)
+	assert.Equal(t, uint64(1), stats.Timeouts)
+	assert.Equal(t, int64(2), stats.MaxWaiters)
+	assert.Equal(t, int64(0), stats.Waiters)
+}
+
+// Verify a named start group is registered
+func Test_NamedStartGroup(t *testing.T) {
+	var registry = metrics.MakeRegistry()
+	var group = MakeNamedStartGroup("start", WithRegistry(registry))
+	var source, ok = registry.Lookup("start")
+	assert.True(t, ok)
+	assert.Equal(t, metrics.Source(group), source)
+}
+
+// Verify a blocked wait is recorded by the watchdog
+func Test_StartGroupWatchdog(t *testing.T) {
+	var dog = watchdog.MakeWatchdog(time.Hour)
+	defer dog.Stop()
+	var group = MakeStartGroup(WithWatchdog(dog))
+	var done = make(chan struct{})
+	go func() {
+		group.Wait()
+		close(done)
+	}()
+	for len(dog.Waits()) == 0 {
+		<-time.After(time.Millisecond)
+	}
+	assert.Equal(t, "start group", dog.Waits()[0].Kind)
+	group.Release()
+	<-done
+	assert.Empty(t, dog.Waits())
+}
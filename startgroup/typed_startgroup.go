@@ -0,0 +1,138 @@
+// Copyright 2026 Jeffrey Bester <jbester@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and
+// to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of
+// the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package startgroup
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"bitbucket.org/jbester/sync/clock"
+	"bitbucket.org/jbester/sync/metrics"
+	"bitbucket.org/jbester/sync/watchdog"
+)
+
+// A TypedStartGroup is a StartGroup whose release event carries a value of type T.  Every
+// goroutine released receives the value passed to Release.
+type TypedStartGroup[T any] interface {
+	//  Release all Waiting goroutines handing each of them the value
+	Release(value T)
+
+	// Wait for a release event, returning its value
+	Wait() T
+
+	// Wait for a release event for up to a timeout, returning its value
+	TimedWait(timeout time.Duration) (T, bool)
+
+	// Wait for a release event until the context is done, returning its value.  Returns the
+	// context's error if the context finished first.
+	WaitContext(ctx context.Context) (T, error)
+
+	// Returns a snapshot of the group's statistics.  Waits that are released are counted as
+	// acquisitions.
+	Stats() metrics.Stats
+}
+
+// A release event and the value it carries.  The value is written before the channel is closed.
+type release[T any] struct {
+	done  chan struct{}
+	value T
+}
+
+type typedStartGroup[T any] struct {
+	lock     *sync.RWMutex
+	pending  *release[T]
+	clock    clock.Clock
+	stats    *metrics.Collector
+	watchdog watchdog.Watchdog
+}
+
+// Create a TypedStartGroup.
+func MakeTypedStartGroup[T any](opts ...Option) TypedStartGroup[T] {
+	var options = makeOptions(opts)
+	return &typedStartGroup[T]{
+		lock:     &sync.RWMutex{},
+		pending:  &release[T]{done: make(chan struct{})},
+		clock:    options.clock,
+		stats:    metrics.MakeCollector(options.clock),
+		watchdog: options.watchdog,
+	}
+}
+
+func (group *typedStartGroup[T]) Release(value T) {
+	var next = &release[T]{done: make(chan struct{})}
+
+	group.lock.Lock()
+	var old = group.pending
+	group.pending = next
+	group.lock.Unlock()
+
+	old.value = value
+	close(old.done)
+}
+
+func (group *typedStartGroup[T]) next() *release[T] {
+	group.lock.RLock()
+	defer group.lock.RUnlock()
+	return group.pending
+}
+
+func (group *typedStartGroup[T]) Wait() T {
+	// join the release before counting as waiting so a routine counted as waiting is
+	// released by the next release
+	var pending = group.next()
+	var wait = group.stats.BeginWait()
+	defer group.watchdog.Begin("start group")()
+	<-pending.done
+	wait.End(metrics.Acquired)
+	return pending.value
+}
+
+func (group *typedStartGroup[T]) TimedWait(timeout time.Duration) (T, bool) {
+	var pending = group.next()
+	var wait = group.stats.BeginWait()
+	defer group.watchdog.Begin("start group")()
+	var timer = group.clock.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-pending.done:
+		wait.End(metrics.Acquired)
+		return pending.value, true
+	case <-timer.C():
+		wait.End(metrics.TimedOut)
+		var zero T
+		return zero, false
+	}
+}
+
+func (group *typedStartGroup[T]) WaitContext(ctx context.Context) (T, error) {
+	var pending = group.next()
+	var wait = group.stats.BeginWait()
+	defer group.watchdog.Begin("start group")()
+	select {
+	case <-pending.done:
+		wait.End(metrics.Acquired)
+		return pending.value, nil
+	case <-ctx.Done():
+		wait.End(metrics.Cancelled)
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+func (group *typedStartGroup[T]) Stats() metrics.Stats {
+	return group.stats.Stats()
+}
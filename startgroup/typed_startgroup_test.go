@@ -0,0 +1,131 @@
+// Copyright 2026 Jeffrey Bester <jbester@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and
+// to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of
+// the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package startgroup
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"bitbucket.org/jbester/sync/clock"
+	"bitbucket.org/jbester/sync/watchdog"
+	"github.com/stretchr/testify/assert"
+)
+
+// wait until the given number of routines are blocked on the typed start group
+func waitForTypedWaiters[T any](group TypedStartGroup[T], count int64) {
+	for group.Stats().Waiters != count {
+		<-time.After(time.Millisecond)
+	}
+}
+
+func Test_TypedReleaseValue(t *testing.T) {
+	var group = MakeTypedStartGroup[string]()
+	var results = make(chan string, 5)
+	for i := 0; i < 5; i++ {
+		go func() {
+			results <- group.Wait()
+		}()
+	}
+	waitForTypedWaiters(group, 5)
+	group.Release("go")
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, "go", <-results)
+	}
+}
+
+func Test_TypedReuse(t *testing.T) {
+	var group = MakeTypedStartGroup[int]()
+	var results = make(chan int)
+	for i := 0; i < 3; i++ {
+		go func() {
+			results <- group.Wait()
+		}()
+		waitForTypedWaiters(group, 1)
+		group.Release(i)
+		assert.Equal(t, i, <-results)
+	}
+	var stats = group.Stats()
+	assert.Equal(t, uint64(3), stats.Acquisitions)
+	assert.Equal(t, int64(0), stats.Waiters)
+}
+
+func Test_TypedTimedWait(t *testing.T) {
+	var group = MakeTypedStartGroup[int]()
+	var value, ok = group.TimedWait(time.Millisecond)
+	assert.False(t, ok)
+	assert.Equal(t, 0, value)
+
+	var results = make(chan int)
+	go func() {
+		var value, ok = group.TimedWait(time.Minute)
+		assert.True(t, ok)
+		results <- value
+	}()
+	waitForTypedWaiters(group, 1)
+	group.Release(5)
+	assert.Equal(t, 5, <-results)
+}
+
+func Test_TypedWaitContext(t *testing.T) {
+	var group = MakeTypedStartGroup[int]()
+	var ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	var _, err = group.WaitContext(ctx)
+	assert.Equal(t, context.Canceled, err)
+
+	var results = make(chan int)
+	go func() {
+		var value, err = group.WaitContext(context.Background())
+		assert.NoError(t, err)
+		results <- value
+	}()
+	waitForTypedWaiters(group, 1)
+	group.Release(9)
+	assert.Equal(t, 9, <-results)
+}
+
+func Test_TypedTimedWaitFakeClock(t *testing.T) {
+	var fake = clock.MakeFakeClock(time.Now())
+	var group = MakeTypedStartGroup[int](WithClock(fake))
+	var result = make(chan bool)
+	go func() {
+		var _, ok = group.TimedWait(time.Hour)
+		result <- ok
+	}()
+	fake.WaitForTimers(1)
+	fake.Advance(time.Hour)
+	assert.False(t, <-result)
+}
+
+// Verify a blocked wait is recorded by the watchdog
+func Test_TypedStartGroupWatchdog(t *testing.T) {
+	var dog = watchdog.MakeWatchdog(time.Hour)
+	defer dog.Stop()
+	var group = MakeTypedStartGroup[int](WithWatchdog(dog))
+	var done = make(chan struct{})
+	go func() {
+		group.Wait()
+		close(done)
+	}()
+	for len(dog.Waits()) == 0 {
+		<-time.After(time.Millisecond)
+	}
+	assert.Equal(t, "start group", dog.Waits()[0].Kind)
+	group.Release(1)
+	<-done
+	assert.Empty(t, dog.Waits())
+}
@@ -0,0 +1,165 @@
+// Copyright 2026 Jeffrey Bester <jbester@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and
+// to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of
+// the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package tasks provides application-level tasks with a priority.  Goroutines have no priority of
+// their own, so a scheduler built on the primitives in this module represents each unit of work as
+// a Task and passes it to the priority-ordered primitives.  Those primitives order waiting tasks
+// by their current priority and reorder them when it changes.
+//
+// A task's effective priority is the higher of its base priority and the priorities it inherits.
+// A priority-inheritance mutex, for example, lends the priority of its highest waiting task to the
+// task holding the mutex so a low priority holder cannot block a high priority waiter
+// indefinitely behind tasks of intermediate priority.
+package tasks
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// A Task is an application-level unit of work with a priority.  Larger values indicate higher
+// priority.
+type Task interface {
+	//  Priority returns the effective priority: the higher of the base priority and every
+	//  inherited priority.
+	Priority() int
+
+	//  BasePriority returns the priority assigned to the task.
+	BasePriority() int
+
+	//  SetPriority changes the priority assigned to the task.
+	SetPriority(priority int)
+
+	//  Inherit makes the effective priority at least the priority returned by the donor until
+	//  the donation is cancelled.  The donor is consulted when the donation is made and on
+	//  each Refresh; it must not call back into the task.
+	Inherit(donor func() int) Donation
+
+	//  Watch calls onChange, from the routine making the change, whenever the effective
+	//  priority changes until the returned function is called.
+	Watch(onChange func()) (cancel func())
+}
+
+// A Donation is a priority inherited by a task.
+type Donation interface {
+	//  Refresh re-evaluates the donated priority after the donor's priority may have changed.
+	Refresh()
+
+	//  Cancel withdraws the donated priority.
+	Cancel()
+}
+
+type task struct {
+	lock      *sync.Mutex
+	base      int
+	effective int64
+	donations map[*donation]bool
+	watchers  map[*watcher]bool
+}
+
+type donation struct {
+	owner *task
+	donor func() int
+}
+
+type watcher struct {
+	onChange func()
+}
+
+// Create a task with the priority.
+func MakeTask(priority int) Task {
+	return &task{
+		lock:      &sync.Mutex{},
+		base:      priority,
+		effective: int64(priority),
+		donations: make(map[*donation]bool),
+		watchers:  make(map[*watcher]bool),
+	}
+}
+
+func (t *task) Priority() int {
+	return int(atomic.LoadInt64(&t.effective))
+}
+
+func (t *task) BasePriority() int {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return t.base
+}
+
+func (t *task) SetPriority(priority int) {
+	t.lock.Lock()
+	t.base = priority
+	t.update()
+}
+
+// Recompute the effective priority and notify the watchers if it changed.  Must be called with the
+// lock held; the lock is released before the watchers are called.
+func (t *task) update() {
+	var priority = t.base
+	for d := range t.donations {
+		if donated := d.donor(); donated > priority {
+			priority = donated
+		}
+	}
+	var changed = atomic.SwapInt64(&t.effective, int64(priority)) != int64(priority)
+	var watchers = make([]*watcher, 0, len(t.watchers))
+	if changed {
+		for w := range t.watchers {
+			watchers = append(watchers, w)
+		}
+	}
+	t.lock.Unlock()
+
+	// notify outside the lock so watchers may query the task or reorder queues holding it
+	for _, w := range watchers {
+		w.onChange()
+	}
+}
+
+func (t *task) Inherit(donor func() int) Donation {
+	var d = &donation{owner: t, donor: donor}
+	t.lock.Lock()
+	t.donations[d] = true
+	t.update()
+	return d
+}
+
+func (t *task) Watch(onChange func()) func() {
+	var w = &watcher{onChange: onChange}
+	t.lock.Lock()
+	t.watchers[w] = true
+	t.lock.Unlock()
+	return func() {
+		t.lock.Lock()
+		delete(t.watchers, w)
+		t.lock.Unlock()
+	}
+}
+
+func (d *donation) Refresh() {
+	d.owner.lock.Lock()
+	if !d.owner.donations[d] {
+		d.owner.lock.Unlock()
+		return
+	}
+	d.owner.update()
+}
+
+func (d *donation) Cancel() {
+	d.owner.lock.Lock()
+	delete(d.owner.donations, d)
+	d.owner.update()
+}
@@ -0,0 +1,85 @@
+// Copyright 2026 Jeffrey Bester <jbester@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and
+// to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of
+// the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package tasks
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SetPriority(t *testing.T) {
+	var task = MakeTask(3)
+	assert.Equal(t, 3, task.Priority())
+	task.SetPriority(7)
+	assert.Equal(t, 7, task.Priority())
+	assert.Equal(t, 7, task.BasePriority())
+}
+
+func Test_Inherit(t *testing.T) {
+	var task = MakeTask(3)
+	var donated = 10
+	var donation = task.Inherit(func() int {
+		return donated
+	})
+	assert.Equal(t, 10, task.Priority())
+	assert.Equal(t, 3, task.BasePriority())
+
+	// a lower donation leaves the base priority in effect
+	donated = 1
+	donation.Refresh()
+	assert.Equal(t, 3, task.Priority())
+
+	donated = 8
+	donation.Refresh()
+	assert.Equal(t, 8, task.Priority())
+	donation.Cancel()
+	assert.Equal(t, 3, task.Priority())
+
+	// a cancelled donation is not consulted again
+	donated = 20
+	donation.Refresh()
+	assert.Equal(t, 3, task.Priority())
+}
+
+func Test_InheritHighestDonation(t *testing.T) {
+	var task = MakeTask(0)
+	var first = task.Inherit(func() int { return 5 })
+	var second = task.Inherit(func() int { return 9 })
+	assert.Equal(t, 9, task.Priority())
+	second.Cancel()
+	assert.Equal(t, 5, task.Priority())
+	first.Cancel()
+	assert.Equal(t, 0, task.Priority())
+}
+
+func Test_Watch(t *testing.T) {
+	var task = MakeTask(1)
+	var changes int32 = 0
+	var cancel = task.Watch(func() {
+		atomic.AddInt32(&changes, 1)
+	})
+	task.SetPriority(2)
+	// unchanged priority is not reported
+	task.SetPriority(2)
+	var donation = task.Inherit(func() int { return 1 })
+	assert.Equal(t, int32(1), atomic.LoadInt32(&changes))
+	donation.Cancel()
+	cancel()
+	task.SetPriority(4)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&changes))
+}
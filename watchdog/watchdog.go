@@ -0,0 +1,252 @@
+// Copyright 2026 Jeffrey Bester <jbester@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and
+// to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of
+// the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package watchdog reports routines that wait on a primitive for longer than a threshold.  A
+// primitive created with a watchdog records each blocked wait together with the stack of the
+// waiting routine, so a hung routine can be traced to the code path that blocked without taking
+// a full goroutine dump.
+package watchdog
+
+import (
+	"log"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"bitbucket.org/jbester/sync/clock"
+)
+
+// A Report describes a wait in progress.
+type Report struct {
+	// The kind of primitive waited on, e.g. "semaphore".
+	Kind string
+
+	// The time the wait began.
+	Started time.Time
+
+	// How long the routine has been waiting.
+	Waited time.Duration
+
+	// The stack of the waiting routine when the wait began.
+	Stack []byte
+}
+
+// A Watchdog records the waits of the primitives created with it and reports waits exceeding
+// its threshold.
+type Watchdog interface {
+	//  Begin records the calling routine starting to wait on a primitive of the kind.  The
+	//  returned function must be called when the wait ends.
+	Begin(kind string) func()
+
+	//  Waits returns the waits in progress, oldest first.
+	Waits() []Report
+
+	//  Stop checking waits.  Waits already in progress are no longer reported.
+	Stop()
+}
+
+// Option configures optional behaviour of a watchdog when it is created.
+type Option func(*options)
+
+type options struct {
+	clock    clock.Clock
+	interval time.Duration
+	reporter func(Report)
+}
+
+// WithClock makes a watchdog use the clock to time waits in place of the system clock.
+func WithClock(c clock.Clock) Option {
+	return func(opts *options) {
+		opts.clock = c
+	}
+}
+
+// WithInterval sets how often a watchdog checks the waits in progress.  The default is the
+// threshold.
+func WithInterval(interval time.Duration) Option {
+	return func(opts *options) {
+		opts.interval = interval
+	}
+}
+
+// WithReporter makes a watchdog call the reporter for each wait exceeding the threshold in place
+// of writing it to the standard logger.  The reporter is called from the watchdog's routine.
+func WithReporter(reporter func(Report)) Option {
+	return func(opts *options) {
+		opts.reporter = reporter
+	}
+}
+
+// Write the report to the standard logger.
+func logReport(report Report) {
+	log.Printf("watchdog: %s wait exceeded threshold after %v, started at:\n%s",
+		report.Kind, report.Waited, report.Stack)
+}
+
+type wait struct {
+	kind     string
+	started  time.Time
+	stack    []byte
+	reported bool
+}
+
+type watchdog struct {
+	lock      *sync.Mutex
+	waits     map[uint64]*wait
+	next      uint64
+	threshold time.Duration
+	interval  time.Duration
+	clock     clock.Clock
+	reporter  func(Report)
+	stop      chan struct{}
+	stopOnce  *sync.Once
+}
+
+// Create a watchdog reporting each wait that lasts longer than the threshold.  A wait is reported
+// once, at the first check after it exceeds the threshold.  The watchdog checks waits from its
+// own routine until stopped.
+func MakeWatchdog(threshold time.Duration, opts ...Option) Watchdog {
+	var options = options{clock: clock.Real(), interval: threshold, reporter: logReport}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.interval <= 0 {
+		panic("watchdog created with interval less than or equal to zero")
+	}
+	var dog = &watchdog{
+		lock:      &sync.Mutex{},
+		waits:     make(map[uint64]*wait),
+		threshold: threshold,
+		interval:  options.interval,
+		clock:     options.clock,
+		reporter:  options.reporter,
+		stop:      make(chan struct{}),
+		stopOnce:  &sync.Once{},
+	}
+	go dog.monitor()
+	return dog
+}
+
+// Returns the stack of the calling routine.
+func captureStack() []byte {
+	var buf = make([]byte, 4096)
+	for {
+		var n = runtime.Stack(buf, false)
+		if n < len(buf) {
+			return buf[:n]
+		}
+		buf = make([]byte, len(buf)*2)
+	}
+}
+
+func (dog *watchdog) Begin(kind string) func() {
+	var record = &wait{kind: kind, started: dog.clock.Now(), stack: captureStack()}
+	dog.lock.Lock()
+	var id = dog.next
+	dog.next++
+	dog.waits[id] = record
+	dog.lock.Unlock()
+
+	return func() {
+		dog.lock.Lock()
+		delete(dog.waits, id)
+		dog.lock.Unlock()
+	}
+}
+
+func (dog *watchdog) report(record *wait, now time.Time) Report {
+	return Report{
+		Kind:    record.kind,
+		Started: record.started,
+		Waited:  now.Sub(record.started),
+		Stack:   record.stack,
+	}
+}
+
+func (dog *watchdog) Waits() []Report {
+	var now = dog.clock.Now()
+	dog.lock.Lock()
+	var reports = make([]Report, 0, len(dog.waits))
+	for _, record := range dog.waits {
+		reports = append(reports, dog.report(record, now))
+	}
+	dog.lock.Unlock()
+	sortByStart(reports)
+	return reports
+}
+
+func sortByStart(reports []Report) {
+	sort.Slice(reports, func(i, j int) bool {
+		return reports[i].Started.Before(reports[j].Started)
+	})
+}
+
+// Report the waits that have exceeded the threshold since the last check.
+func (dog *watchdog) check() {
+	var now = dog.clock.Now()
+	var overdue []Report
+	dog.lock.Lock()
+	for _, record := range dog.waits {
+		if !record.reported && now.Sub(record.started) >= dog.threshold {
+			record.reported = true
+			overdue = append(overdue, dog.report(record, now))
+		}
+	}
+	dog.lock.Unlock()
+
+	// report outside the lock so the reporter may begin or end waits
+	sortByStart(overdue)
+	for _, report := range overdue {
+		dog.reporter(report)
+	}
+}
+
+func (dog *watchdog) monitor() {
+	for {
+		var timer = dog.clock.NewTimer(dog.interval)
+		select {
+		case <-timer.C():
+			dog.check()
+		case <-dog.stop:
+			timer.Stop()
+			return
+		}
+	}
+}
+
+func (dog *watchdog) Stop() {
+	dog.stopOnce.Do(func() {
+		close(dog.stop)
+	})
+}
+
+type disabled struct{}
+
+// Returns a watchdog that records nothing.  Primitives created without a watchdog use it.
+func Disabled() Watchdog {
+	return disabled{}
+}
+
+func (disabled) Begin(kind string) func() {
+	return func() {}
+}
+
+func (disabled) Waits() []Report {
+	return nil
+}
+
+func (disabled) Stop() {
+}
@@ -0,0 +1,114 @@
+// Copyright 2026 Jeffrey Bester <jbester@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated
+// documentation files (the "Software"), to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and
+// to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all copies or substantial portions of
+// the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE
+// WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS
+// OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR
+// OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package watchdog
+
+import (
+	"testing"
+	"time"
+
+	"bitbucket.org/jbester/sync/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ReportLongWait(t *testing.T) {
+	var fake = clock.MakeFakeClock(time.Now())
+	var reports = make(chan Report, 1)
+	var dog = MakeWatchdog(time.Second, WithClock(fake), WithReporter(func(report Report) {
+		reports <- report
+	}))
+	defer dog.Stop()
+
+	var end = dog.Begin("semaphore")
+	fake.WaitForTimers(1)
+	fake.Advance(time.Second)
+	var report = <-reports
+	assert.Equal(t, "semaphore", report.Kind)
+	assert.Equal(t, time.Second, report.Waited)
+	assert.Contains(t, string(report.Stack), "Test_ReportLongWait")
+	end()
+	assert.Empty(t, dog.Waits())
+}
+
+func Test_ReportOnce(t *testing.T) {
+	var fake = clock.MakeFakeClock(time.Now())
+	var reports = make(chan Report, 2)
+	var dog = MakeWatchdog(time.Second, WithClock(fake), WithReporter(func(report Report) {
+		reports <- report
+	}))
+	defer dog.Stop()
+
+	var first = dog.Begin("first")
+	defer first()
+	fake.WaitForTimers(1)
+	fake.Advance(time.Second)
+	assert.Equal(t, "first", (<-reports).Kind)
+
+	var second = dog.Begin("second")
+	defer second()
+	fake.WaitForTimers(1)
+	fake.Advance(time.Second)
+	// the first wait is not reported again
+	assert.Equal(t, "second", (<-reports).Kind)
+	assert.Len(t, dog.Waits(), 2)
+}
+
+func Test_ShortWaitNotReported(t *testing.T) {
+	var fake = clock.MakeFakeClock(time.Now())
+	var reports = make(chan Report, 1)
+	var dog = MakeWatchdog(time.Second, WithClock(fake), WithInterval(time.Millisecond*100),
+		WithReporter(func(report Report) {
+			reports <- report
+		}))
+	defer dog.Stop()
+
+	var end = dog.Begin("semaphore")
+	fake.WaitForTimers(1)
+	fake.Advance(time.Millisecond * 100)
+	end()
+	assert.Empty(t, dog.Waits())
+	fake.WaitForTimers(1)
+	fake.Advance(time.Second)
+	fake.WaitForTimers(1)
+	select {
+	case report := <-reports:
+		assert.Fail(t, "short wait reported", report.Kind)
+	default:
+	}
+}
+
+func Test_Waits(t *testing.T) {
+	var fake = clock.MakeFakeClock(time.Now())
+	var dog = MakeWatchdog(time.Hour, WithClock(fake))
+	defer dog.Stop()
+	var first = dog.Begin("first")
+	fake.Advance(time.Second)
+	var second = dog.Begin("second")
+	var waits = dog.Waits()
+	assert.Len(t, waits, 2)
+	assert.Equal(t, "first", waits[0].Kind)
+	assert.Equal(t, time.Second, waits[0].Waited)
+	assert.Equal(t, "second", waits[1].Kind)
+	first()
+	second()
+	assert.Empty(t, dog.Waits())
+}
+
+func Test_Disabled(t *testing.T) {
+	var dog = Disabled()
+	dog.Begin("semaphore")()
+	assert.Empty(t, dog.Waits())
+	dog.Stop()
+}